@@ -0,0 +1,176 @@
+package linkedpackage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNotAsar is returned when the file is not Electron's asar archive.
+var ErrNotAsar = errors.New("not an asar archive")
+
+type asarEntry struct {
+	Files      map[string]*asarEntry `json:"files"`
+	Size       int64                 `json:"size"`
+	Offset     string                `json:"offset"`
+	Unpacked   bool                  `json:"unpacked"`
+	Executable bool                  `json:"executable"`
+	Link       string                `json:"link"`
+}
+
+// Asar is the file system of Electron application's archive (e.g. resources/app.asar).
+// Files that are marked as unpacked are read from "<archive>.unpacked" folder.
+type Asar struct {
+	*virtualFS
+	path string
+	// base is the offset of the file contents
+	base int64
+}
+
+// OpenAsar reads the header of the asar archive.
+//
+// The header is Chromium's pickle: uint32 4, uint32 size of the header pickle,
+// uint32 size of the payload and uint32 length of the JSON string. The contents follow the header pickle.
+func OpenAsar(archivePath string) (*Asar, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var sizes [16]byte
+	if _, err := io.ReadFull(f, sizes[:]); err != nil {
+		return nil, fmt.Errorf("%s: %w", archivePath, ErrNotAsar)
+	}
+	headerSize := binary.LittleEndian.Uint32(sizes[4:8])
+	jsonSize := binary.LittleEndian.Uint32(sizes[12:16])
+	if binary.LittleEndian.Uint32(sizes[0:4]) != 4 || jsonSize+8 > headerSize {
+		return nil, fmt.Errorf("%s: %w", archivePath, ErrNotAsar)
+	}
+	header := make([]byte, jsonSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return nil, fmt.Errorf("%s: %w", archivePath, err)
+	}
+	var root asarEntry
+	if err := json.Unmarshal(header, &root); err != nil || root.Files == nil {
+		return nil, fmt.Errorf("%s: %w", archivePath, ErrNotAsar)
+	}
+	a := &Asar{
+		virtualFS: newVirtualFS(),
+		path:      archivePath,
+		base:      8 + int64(headerSize),
+	}
+	if err := a.addEntries("", &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", archivePath, err)
+	}
+	return a, nil
+}
+
+func (a *Asar) addEntries(dir string, entry *asarEntry) error {
+	for childName, child := range entry.Files {
+		if childName == "" || childName == "." || childName == ".." || strings.Contains(childName, "/") {
+			return fmt.Errorf("invalid file name %q in %q", childName, dir)
+		}
+		name := path.Join(dir, childName)
+		switch {
+		case child.Files != nil:
+			a.add(name, &virtualEntry{mode: fs.ModeDir | 0755})
+			if err := a.addEntries(name, child); err != nil {
+				return err
+			}
+		case child.Link != "":
+			// links are relative to the root of the archive
+			a.add(name, &virtualEntry{mode: fs.ModeSymlink | 0777, link: "/" + child.Link})
+		default:
+			opener, err := a.opener(name, child)
+			if err != nil {
+				return err
+			}
+			mode := fs.FileMode(0644)
+			if child.Executable {
+				mode = 0755
+			}
+			a.add(name, &virtualEntry{mode: mode, size: child.Size, open: opener})
+		}
+	}
+	return nil
+}
+
+func (a *Asar) opener(name string, entry *asarEntry) (func() (io.ReadCloser, error), error) {
+	if entry.Unpacked {
+		return func() (io.ReadCloser, error) {
+			return os.Open(filepath.Join(a.path+".unpacked", filepath.FromSlash(name)))
+		}, nil
+	}
+	offset, err := strconv.ParseInt(entry.Offset, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid offset of %s: %w", name, err)
+	}
+	size := entry.Size
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(a.path)
+		if err != nil {
+			return nil, err
+		}
+		return asarReadCloser{
+			Reader: io.NewSectionReader(f, a.base+offset, size),
+			Closer: f,
+		}, nil
+	}, nil
+}
+
+type asarReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+var (
+	asarCache     = map[string]*Asar{}
+	asarCacheLock sync.Mutex
+)
+
+func openAsarCached(archivePath string) (*Asar, error) {
+	asarCacheLock.Lock()
+	defer asarCacheLock.Unlock()
+	if a, ok := asarCache[archivePath]; ok {
+		return a, nil
+	}
+	a, err := OpenAsar(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	asarCache[archivePath] = a
+	return a, nil
+}
+
+// splitAsarPath splits the path that goes through the asar archive (e.g. "resources/app.asar/dist/main.js")
+// into the archive and the path inside it. Like Electron's fs module, the archive is treated as a folder.
+func splitAsarPath(osPath string) (*Asar, string, bool) {
+	if !strings.Contains(osPath, ".asar") {
+		return nil, "", false
+	}
+	parts := strings.Split(filepath.Clean(osPath), string(filepath.Separator))
+	for i, part := range parts {
+		if !strings.HasSuffix(part, ".asar") {
+			continue
+		}
+		archivePath := strings.Join(parts[:i+1], string(filepath.Separator))
+		if info, err := os.Stat(archivePath); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		a, err := openAsarCached(archivePath)
+		if err != nil {
+			return nil, "", false
+		}
+		return a, fsPath(strings.Join(parts[i+1:], "/")), true
+	}
+	return nil, "", false
+}
@@ -0,0 +1,98 @@
+package linkedpackage
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testAsar = filepath.Join("testdata", "electron", "resources", "app.asar")
+
+func TestOpenAsar(t *testing.T) {
+	a, err := OpenAsar(testAsar)
+	if !assert.NoError(t, err) {
+		return
+	}
+	entries, err := a.ReadDir("node_modules")
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"alias-pad", "is-number", "left-pad", "native-addon"}, names)
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "packed file",
+			file: "node_modules/left-pad/index.js",
+			want: "module.exports = function leftPad(str, len) { return str; };\n",
+		},
+		{
+			name: "unpacked file",
+			file: "node_modules/native-addon/LICENSE",
+			want: "MIT License\n\nCopyright (c) 2022 Native Team\n",
+		},
+		{
+			name: "link",
+			file: "node_modules/alias-pad/index.js",
+			want: "module.exports = function leftPad(str, len) { return str; };\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := a.Open(tt.file)
+			if assert.NoError(t, err) {
+				defer f.Close()
+				content, err := ioutil.ReadAll(f)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, string(content))
+			}
+		})
+	}
+
+	_, err = a.Open("node_modules/missing/index.js")
+	assert.Error(t, err)
+	// folders can be opened but not read
+	_, err = fs.ReadFile(a, "dist")
+	assert.Error(t, err)
+
+	_, err = OpenAsar(filepath.Join("testdata", "rust", "Cargo.lock"))
+	assert.True(t, errors.Is(err, ErrNotAsar))
+}
+
+func TestSearch_asar(t *testing.T) {
+	resources := filepath.Join("testdata", "electron", "resources")
+	assert.Equal(t, []string{filepath.Join(testAsar, "dist", "renderer.js.map")}, Search(resources, ".js.map"))
+	// files in app.asar.unpacked are found once through the archive
+	assert.Equal(t, []string{filepath.Join(testAsar, "node_modules", "native-addon", "build", "Release", "addon.node")}, Search(resources, ".node"))
+	assert.Equal(t, []string{filepath.Join(testAsar, "dist", "main.js"), filepath.Join(testAsar, "dist", "renderer.js")}, Search(filepath.Join(testAsar, "dist"), ".js"))
+}
+
+func TestParseJS_asar(t *testing.T) {
+	modules, err := ParseJSWebPack(filepath.Join(testAsar, "dist", "main.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{{Lang: "js", Name: "left-pad", Path: "/node_modules/left-pad"}}, modules)
+
+	modules, err = ParseJSSourcemapFile(filepath.Join(testAsar, "dist", "renderer.js.map"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{{Lang: "js", Name: "is-number", Path: "/node_modules/is-number"}}, modules)
+}
+
+func Test_projectJSConfigReader_asar(t *testing.T) {
+	module := Module{Lang: "js", Name: "native-addon", Path: "/node_modules/native-addon"}
+	assert.NoError(t, ReadProjectData(&module, testAsar))
+	assert.Equal(t, "MIT", module.LicenseName)
+	assert.Equal(t, "2.0.0", module.Version)
+	assert.Equal(t, "Native Team", module.Author)
+	// LICENSE is in app.asar.unpacked
+	assert.Equal(t, "MIT License\n\nCopyright (c) 2022 Native Team", module.LicenseContent)
+	assert.Empty(t, module.DirHash)
+}
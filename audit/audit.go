@@ -0,0 +1,234 @@
+// Package audit matches vulnerability reports with the modules linked into the application and exports the result.
+package audit
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/future-architect/linkedpackage/semver"
+)
+
+// Finding is the vulnerability that affects the linked module.
+type Finding struct {
+	Module        linkedpackage.Module
+	Vulnerability npmaudit.Vulnerability
+	// Advisories are root advisories resolved from Vulnerability's Cause and CausedBy
+	Advisories []Advisory
+	// Cycles are dependency cycles found while resolving advisories
+	Cycles [][]string
+}
+
+// Suppressed returns true if all advisories of the finding are ignored.
+func (f Finding) Suppressed() bool {
+	if len(f.Advisories) == 0 {
+		return false
+	}
+	for _, a := range f.Advisories {
+		if a.Ignored == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Severity returns the most severe level of the advisories that are not ignored. The vulnerability's severity
+// is used when the advisories are unknown.
+func (f Finding) Severity() string {
+	if len(f.Advisories) == 0 {
+		return f.Vulnerability.Severity
+	}
+	result := ""
+	for _, a := range f.Advisories {
+		if a.Ignored == nil && SeverityLevel(a.Severity) > SeverityLevel(result) {
+			result = a.Severity
+		}
+	}
+	return result
+}
+
+// Match returns findings of the linked modules.
+//
+// A vulnerability is matched with the module only when it is about the installed copy that is linked.
+// Vulnerabilities are matched with the modules of the same language (Vulnerability.Lang), so reports of npm, yarn
+// and pnpm are used only for "js" modules.
+// Install paths (Vulnerability.Nodes) are compared first, and semver ranges are evaluated against Module.Version
+// when the report doesn't have install paths.
+func Match(modules []linkedpackage.Module, report *npmaudit.AuditReport) []Finding {
+	audits := map[string]npmaudit.Vulnerability{}
+	for _, r := range report.Vulnerabilities {
+		audits[r.Key()] = r
+	}
+	var result []Finding
+	for _, m := range modules {
+		v, ok := audits[npmaudit.VulnerabilityKey(m.Lang, m.Name)]
+		if !ok || !affects(m, v) {
+			continue
+		}
+		advisories, cycles := ResolveAdvisories(report, v.Key())
+		if v.JS() {
+			advisories = filterAdvisories(m, advisories)
+		}
+		if len(advisories) == 0 && len(v.CausedBy) == 0 {
+			// all advisories are for other versions
+			continue
+		}
+		result = append(result, Finding{
+			Module:        m,
+			Vulnerability: v,
+			Advisories:    advisories,
+			Cycles:        cycles,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Module.Name < result[j].Module.Name
+	})
+	return result
+}
+
+// affects checks the vulnerability is about the module's installed copy.
+// Ranges of other languages are not npm's semver ranges. They are evaluated by the database (e.g. OSV)
+// and Nodes have the install paths of the affected modules.
+func affects(m linkedpackage.Module, v npmaudit.Vulnerability) bool {
+	if !v.JS() {
+		return contains(v.Nodes, strings.TrimPrefix(filepath.ToSlash(m.Path), "/"))
+	}
+	if len(v.Nodes) > 0 {
+		installPath := strings.TrimPrefix(filepath.ToSlash(m.Path), "/")
+		for _, node := range v.Nodes {
+			if node == installPath {
+				return true
+			}
+			if v.LogicalNodes && hoistedFrom(installPath, node) {
+				// the hoisted copy can be other version
+				return inRange(m.Version, v.Range)
+			}
+		}
+		if strings.HasPrefix(installPath, "node_modules/") {
+			return false
+		}
+		// the path can't be compared with nodes (e.g. workspaces). use the version instead
+	}
+	return inRange(m.Version, v.Range)
+}
+
+func contains(list []string, value string) bool {
+	return indexOf(list, value) != -1
+}
+
+// hoistedFrom returns true if the package of the node can be installed at installPath by hoisting.
+// The packages in installPath should be on the node's dependency path and the last package should be the same.
+// pnpm's virtual store folders (node_modules/.pnpm/name@version) are skipped.
+func hoistedFrom(installPath, node string) bool {
+	installed := installedPackages(installPath)
+	path := installedPackages(node)
+	if len(installed) == 0 || len(path) == 0 || installed[len(installed)-1] != path[len(path)-1] {
+		return false
+	}
+	i := 0
+	for _, name := range path {
+		if i < len(installed) && installed[i] == name {
+			i++
+		}
+	}
+	return i == len(installed)
+}
+
+func installedPackages(installPath string) []string {
+	var result []string
+	for _, name := range strings.Split(strings.TrimPrefix(installPath, "node_modules/"), "/node_modules/") {
+		if !strings.HasPrefix(name, ".") {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// filterAdvisories removes direct advisories whose vulnerable range doesn't contain the module's version.
+func filterAdvisories(m linkedpackage.Module, advisories []Advisory) []Advisory {
+	var result []Advisory
+	for _, a := range advisories {
+		if a.Transitive() || inRange(m.Version, a.Range) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// inRange returns true if the version is in the range. It also returns true if it can't be evaluated
+// not to hide vulnerabilities.
+func inRange(version, rangeSrc string) bool {
+	if version == "" || rangeSrc == "" {
+		return true
+	}
+	ok, err := semver.Satisfies(version, rangeSrc)
+	if err != nil {
+		return true
+	}
+	return ok
+}
+
+// AdvisoryID returns GHSA ID if the advisory URL is GitHub's one, otherwise it returns npm's advisory ID.
+func AdvisoryID(via npmaudit.Via) string {
+	if i := strings.LastIndex(via.URL, "/GHSA-"); i != -1 {
+		return via.URL[i+1:]
+	}
+	if via.Source != 0 {
+		return "NPM-" + strconv.Itoa(via.Source)
+	}
+	return via.URL
+}
+
+// SeverityLevel returns the order of severity. The more severe, the larger the value.
+// It returns 0 for unknown severity.
+func SeverityLevel(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return len(Severities) - i
+		}
+	}
+	return 0
+}
+
+// Summarize counts findings per severity. Suppressed findings are not counted, and ignored advisories
+// don't raise the severity of the finding.
+func Summarize(findings []Finding) npmaudit.Vulnerabilities {
+	var result npmaudit.Vulnerabilities
+	for _, f := range findings {
+		if f.Suppressed() {
+			continue
+		}
+		switch f.Severity() {
+		case "critical":
+			result.Critical++
+		case "high":
+			result.High++
+		case "moderate":
+			result.Moderate++
+		case "low":
+			result.Low++
+		case "info":
+			result.Info++
+		}
+		result.Total++
+	}
+	return result
+}
+
+// Exceeds returns true if any finding is as severe as or more severe than the threshold.
+// Suppressed findings and ignored advisories are not counted.
+func Exceeds(findings []Finding, threshold string) bool {
+	level := SeverityLevel(threshold)
+	if level == 0 {
+		return false
+	}
+	for _, f := range findings {
+		if !f.Suppressed() && SeverityLevel(f.Severity()) >= level {
+			return true
+		}
+	}
+	return false
+}
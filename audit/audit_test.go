@@ -0,0 +1,268 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+var lodashVia = npmaudit.Via{
+	Source:     1523,
+	Name:       "lodash",
+	Dependency: "lodash",
+	Title:      "Prototype Pollution",
+	URL:        "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+	Severity:   "high",
+	Range:      "<4.17.19",
+}
+
+var testReport = &npmaudit.AuditReport{
+	AuditReportVersion: 2,
+	Vulnerabilities: map[string]npmaudit.Vulnerability{
+		"lodash": {
+			Name:     "lodash",
+			Severity: "high",
+			Range:    "<4.17.19",
+			Nodes:    []string{"node_modules/lodash"},
+			Cause:    []npmaudit.Via{lodashVia},
+		},
+		"extract-zip": {
+			Name:     "extract-zip",
+			Severity: "low",
+			Range:    "<=1.6.7",
+			Nodes:    []string{"node_modules/extract-zip"},
+			CausedBy: []string{"mkdirp"},
+		},
+	},
+}
+
+var testModules = []linkedpackage.Module{
+	{Lang: "js", Name: "vue", Path: "/node_modules/vue", Version: "2.6.12"},
+	{Lang: "js", Name: "lodash", Path: "/node_modules/lodash", Version: "4.17.15"},
+}
+
+func TestMatch(t *testing.T) {
+	got := Match(testModules, testReport)
+	assert.Equal(t, []Finding{
+		{
+			Module:        testModules[1],
+			Vulnerability: testReport.Vulnerabilities["lodash"],
+			Advisories: []Advisory{
+				{Via: lodashVia, Path: []string{"lodash"}},
+			},
+		},
+	}, got)
+}
+
+func TestMatch_lang(t *testing.T) {
+	netVia := npmaudit.Via{
+		Name:     "golang.org/x/net",
+		Title:    "HTTP/2 rapid reset can cause excessive work",
+		URL:      "https://github.com/advisories/GHSA-4374-p667-p6c8",
+		Severity: "high",
+		Range:    "<0.17.0",
+	}
+	report := &npmaudit.AuditReport{
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"lodash": testReport.Vulnerabilities["lodash"],
+			"go/golang.org/x/net": {
+				Lang:     "go",
+				Name:     "golang.org/x/net",
+				Severity: "high",
+				Range:    "<0.17.0",
+				Nodes:    []string{"golang.org/x/net"},
+				Cause:    []npmaudit.Via{netVia},
+			},
+		},
+	}
+	jsLodash := linkedpackage.Module{Lang: "js", Name: "lodash", Path: "/node_modules/lodash", Version: "4.17.15"}
+	pyLodash := linkedpackage.Module{Lang: "python", Name: "lodash", Path: "/lodash", Version: "4.17.15"}
+	goNet := linkedpackage.Module{Lang: "go", Name: "golang.org/x/net", Path: "/golang.org/x/net", Version: "0.10.0"}
+	jsNet := linkedpackage.Module{Lang: "js", Name: "golang.org/x/net", Path: "/node_modules/golang.org/x/net", Version: "0.10.0"}
+	var got []linkedpackage.Module
+	for _, f := range Match([]linkedpackage.Module{jsLodash, pyLodash, goNet, jsNet}, report) {
+		got = append(got, f.Module)
+		if f.Module.Lang == "go" {
+			assert.Equal(t, []Advisory{{Via: netVia, Path: []string{"golang.org/x/net"}}}, f.Advisories)
+		}
+	}
+	assert.Equal(t, []linkedpackage.Module{goNet, jsLodash}, got)
+}
+
+func TestMatch_version(t *testing.T) {
+	debugVia := npmaudit.Via{
+		Source:   534,
+		Name:     "debug",
+		Title:    "Regular Expression Denial of Service",
+		URL:      "https://github.com/advisories/GHSA-gxpj-cx7g-858c",
+		Severity: "low",
+		Range:    "<2.6.9",
+	}
+	nestedReport := &npmaudit.AuditReport{
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"debug": {
+				Name:     "debug",
+				Severity: "low",
+				Range:    "<2.6.9",
+				Nodes:    []string{"node_modules/send/node_modules/debug"},
+				Cause:    []npmaudit.Via{debugVia},
+			},
+		},
+	}
+	// report without install paths (e.g. yarn, pnpm)
+	noNodesReport := &npmaudit.AuditReport{
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"debug": {
+				Name:     "debug",
+				Severity: "low",
+				Range:    "<2.6.9",
+				Cause:    []npmaudit.Via{debugVia},
+			},
+		},
+	}
+	// yarn's dependency path "express>send>debug"
+	logicalReport := &npmaudit.AuditReport{
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"debug": {
+				Name:         "debug",
+				Severity:     "low",
+				Range:        "<2.6.9",
+				Nodes:        []string{"node_modules/express/node_modules/send/node_modules/debug"},
+				LogicalNodes: true,
+				Cause:        []npmaudit.Via{debugVia},
+			},
+		},
+	}
+	hoisted := linkedpackage.Module{Lang: "js", Name: "debug", Path: "/node_modules/debug", Version: "4.3.4"}
+	nested := linkedpackage.Module{Lang: "js", Name: "debug", Path: "/node_modules/send/node_modules/debug", Version: "2.6.8"}
+	other := linkedpackage.Module{Lang: "js", Name: "debug", Path: "/node_modules/mocha/node_modules/debug", Version: "2.6.8"}
+	pnpm := linkedpackage.Module{Lang: "js", Name: "debug", Path: "/node_modules/.pnpm/debug@2.6.8/node_modules/debug", Version: "2.6.8"}
+	tests := []struct {
+		name    string
+		modules []linkedpackage.Module
+		report  *npmaudit.AuditReport
+		want    []linkedpackage.Module
+	}{
+		{
+			name:    "linked copy is not the vulnerable one",
+			modules: []linkedpackage.Module{hoisted},
+			report:  nestedReport,
+			want:    nil,
+		},
+		{
+			name:    "linked copy is the vulnerable one",
+			modules: []linkedpackage.Module{hoisted, nested},
+			report:  nestedReport,
+			want:    []linkedpackage.Module{nested},
+		},
+		{
+			name:    "version is out of the range",
+			modules: []linkedpackage.Module{hoisted},
+			report:  noNodesReport,
+			want:    nil,
+		},
+		{
+			name:    "version is in the range",
+			modules: []linkedpackage.Module{hoisted, nested},
+			report:  noNodesReport,
+			want:    []linkedpackage.Module{nested},
+		},
+		{
+			name:    "hoisted copy on the dependency path",
+			modules: []linkedpackage.Module{hoisted, nested, other},
+			report:  logicalReport,
+			want:    []linkedpackage.Module{nested},
+		},
+		{
+			name:    "pnpm virtual store",
+			modules: []linkedpackage.Module{pnpm},
+			report:  logicalReport,
+			want:    []linkedpackage.Module{pnpm},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []linkedpackage.Module
+			for _, f := range Match(tt.modules, tt.report) {
+				got = append(got, f.Module)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdvisoryID(t *testing.T) {
+	tests := []struct {
+		name string
+		via  npmaudit.Via
+		want string
+	}{
+		{
+			name: "GitHub advisory",
+			via:  lodashVia,
+			want: "GHSA-p6mc-m468-83gw",
+		},
+		{
+			name: "npm advisory",
+			via:  npmaudit.Via{Source: 1523, URL: "https://npmjs.com/advisories/1523"},
+			want: "NPM-1523",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AdvisoryID(tt.via))
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	findings := []Finding{
+		{Vulnerability: npmaudit.Vulnerability{Severity: "high"}},
+		{Vulnerability: npmaudit.Vulnerability{Severity: "low"}},
+		{Vulnerability: npmaudit.Vulnerability{Severity: "high"}},
+	}
+	assert.Equal(t, npmaudit.Vulnerabilities{High: 2, Low: 1, Total: 3}, Summarize(findings))
+}
+
+func TestFinding_Severity(t *testing.T) {
+	critical := npmaudit.Via{Name: "lodash", URL: "https://github.com/advisories/GHSA-35jh-r3h4-6jhm", Severity: "critical"}
+	low := npmaudit.Via{Name: "lodash", URL: "https://github.com/advisories/GHSA-29mw-wpgm-hmr9", Severity: "low"}
+	// the critical advisory is ignored, and only the low one is left
+	partially := Finding{
+		Vulnerability: npmaudit.Vulnerability{Severity: "critical"},
+		Advisories: []Advisory{
+			{Via: critical, Path: []string{"lodash"}, Ignored: &Ignore{Advisory: "GHSA-35jh-r3h4-6jhm", Reason: "not used"}},
+			{Via: low, Path: []string{"lodash"}},
+		},
+	}
+	assert.Equal(t, "low", partially.Severity())
+	assert.Equal(t, npmaudit.Vulnerabilities{Low: 1, Total: 1}, Summarize([]Finding{partially}))
+	assert.False(t, Exceeds([]Finding{partially}, "high"))
+	assert.True(t, Exceeds([]Finding{partially}, "low"))
+
+	unknown := Finding{Vulnerability: npmaudit.Vulnerability{Severity: "high"}}
+	assert.Equal(t, "high", unknown.Severity())
+}
+
+func TestExceeds(t *testing.T) {
+	findings := []Finding{
+		{Vulnerability: npmaudit.Vulnerability{Severity: "moderate"}},
+	}
+	tests := []struct {
+		threshold string
+		want      bool
+	}{
+		{threshold: "", want: false},
+		{threshold: "low", want: true},
+		{threshold: "moderate", want: true},
+		{threshold: "high", want: false},
+		{threshold: "critical", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.threshold, func(t *testing.T) {
+			assert.Equal(t, tt.want, Exceeds(findings, tt.threshold))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Fix is the upgrade that fixes vulnerabilities of linked packages.
+type Fix struct {
+	// Name is the package to upgrade
+	Name string
+	// Version is the version to upgrade to. It is empty when `npm audit fix` can update the package
+	// within the range of package.json
+	Version       string
+	IsSemVerMajor bool
+	// Findings are the linked vulnerable packages that the upgrade fixes
+	Findings []Finding
+	// AdvisoryIDs are the linked advisories that the upgrade clears
+	AdvisoryIDs []string
+	// Severity is the most severe level of the advisories
+	Severity string
+}
+
+// String returns text like "webpack@5.0.0 (semver major)".
+func (f Fix) String() string {
+	result := f.Name
+	if f.Version != "" {
+		result += "@" + f.Version
+	} else {
+		result += " (compatible update)"
+	}
+	if f.IsSemVerMajor {
+		result += " (semver major)"
+	}
+	return result
+}
+
+// FixPlan groups findings by the upgrade that fixes them. Suppressed findings are not included.
+// The upgrade that clears the more advisories comes first. Findings without fix are returned as the second result.
+func FixPlan(findings []Finding) ([]Fix, []Finding) {
+	var fixes []*Fix
+	var unfixable []Finding
+	index := map[string]*Fix{}
+	for _, f := range findings {
+		if f.Suppressed() {
+			continue
+		}
+		fa := f.Vulnerability.FixAvailable
+		if fa == nil {
+			unfixable = append(unfixable, f)
+			continue
+		}
+		name := fa.Name
+		if name == "" {
+			// "fixAvailable": true
+			name = f.Module.Name
+		}
+		key := fmt.Sprintf("%s\x00%s\x00%v", name, fa.Version, fa.IsSemVerMajor)
+		fix, ok := index[key]
+		if !ok {
+			fix = &Fix{
+				Name:          name,
+				Version:       fa.Version,
+				IsSemVerMajor: fa.IsSemVerMajor,
+			}
+			index[key] = fix
+			fixes = append(fixes, fix)
+		}
+		fix.Findings = append(fix.Findings, f)
+		for _, a := range f.Advisories {
+			if a.Ignored != nil {
+				continue
+			}
+			id := AdvisoryID(a.Via)
+			if indexOf(fix.AdvisoryIDs, id) == -1 {
+				fix.AdvisoryIDs = append(fix.AdvisoryIDs, id)
+			}
+		}
+		// ignored advisories don't raise the severity
+		if severity := f.Severity(); SeverityLevel(severity) > SeverityLevel(fix.Severity) {
+			fix.Severity = severity
+		}
+	}
+	result := make([]Fix, 0, len(fixes))
+	for _, fix := range fixes {
+		sort.Strings(fix.AdvisoryIDs)
+		result = append(result, *fix)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if len(result[i].AdvisoryIDs) != len(result[j].AdvisoryIDs) {
+			return len(result[i].AdvisoryIDs) > len(result[j].AdvisoryIDs)
+		}
+		if result[i].IsSemVerMajor != result[j].IsSemVerMajor {
+			return !result[i].IsSemVerMajor
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, unfixable
+}
+
+// WriteFixPlan exports the fix plan of the findings. Plain, JSON and Markdown formats are supported.
+func WriteFixPlan(w io.Writer, format string, findings []Finding) error {
+	fixes, unfixable := FixPlan(findings)
+	switch format {
+	case FormatPlain:
+		return writePlainFixPlan(w, fixes, unfixable)
+	case FormatJSON:
+		return writeJSONFixPlan(w, fixes, unfixable)
+	case FormatMarkdown:
+		return writeMarkdownFixPlan(w, fixes, unfixable)
+	}
+	return fmt.Errorf("fix plan doesn't support %s format", format)
+}
+
+func linkedPackages(findings []Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Module.Name+"@"+f.Module.Version)
+	}
+	return result
+}
+
+func writePlainFixPlan(w io.Writer, fixes []Fix, unfixable []Finding) error {
+	for _, fix := range fixes {
+		fmt.Fprintf(w, "------\n")
+		fmt.Fprintf(w, "[%s] %s\n", fix.Severity, fix)
+		fmt.Fprintf(w, "    clears %d linked advisories: %s\n", len(fix.AdvisoryIDs), strings.Join(fix.AdvisoryIDs, ", "))
+		fmt.Fprintf(w, "    fixes: %s\n", strings.Join(linkedPackages(fix.Findings), ", "))
+	}
+	if len(unfixable) > 0 {
+		fmt.Fprintf(w, "------\n")
+		fmt.Fprintf(w, "no fix available: %s\n", strings.Join(linkedPackages(unfixable), ", "))
+	}
+	major := 0
+	for _, fix := range fixes {
+		if fix.IsSemVerMajor {
+			major++
+		}
+	}
+	fmt.Fprintf(w, "======\n")
+	fmt.Fprintf(w, "%d upgrades (%d semver major) fix linked vulnerabilities, %d vulnerable packages have no fix\n", len(fixes), major, len(unfixable))
+	return nil
+}
+
+type jsonFixPlan struct {
+	Fixes     []jsonFix    `json:"fixes"`
+	Unfixable []jsonModule `json:"unfixable"`
+}
+
+type jsonFix struct {
+	Name          string       `json:"name"`
+	Version       string       `json:"version,omitempty"`
+	IsSemVerMajor bool         `json:"isSemVerMajor"`
+	Severity      string       `json:"severity"`
+	Advisories    []string     `json:"advisories"`
+	Modules       []jsonModule `json:"modules"`
+}
+
+func newJSONModule(f Finding) jsonModule {
+	return jsonModule{
+		Name:    f.Module.Name,
+		Version: f.Module.Version,
+		Path:    f.Module.Path,
+		PURL:    f.Module.PURL(),
+		License: f.Module.LicenseName,
+	}
+}
+
+func writeJSONFixPlan(w io.Writer, fixes []Fix, unfixable []Finding) error {
+	plan := jsonFixPlan{
+		Fixes:     []jsonFix{},
+		Unfixable: []jsonModule{},
+	}
+	for _, fix := range fixes {
+		jf := jsonFix{
+			Name:          fix.Name,
+			Version:       fix.Version,
+			IsSemVerMajor: fix.IsSemVerMajor,
+			Severity:      fix.Severity,
+			Advisories:    fix.AdvisoryIDs,
+			Modules:       []jsonModule{},
+		}
+		if jf.Advisories == nil {
+			jf.Advisories = []string{}
+		}
+		for _, f := range fix.Findings {
+			jf.Modules = append(jf.Modules, newJSONModule(f))
+		}
+		plan.Fixes = append(plan.Fixes, jf)
+	}
+	for _, f := range unfixable {
+		plan.Unfixable = append(plan.Unfixable, newJSONModule(f))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plan)
+}
+
+func writeMarkdownFixPlan(w io.Writer, fixes []Fix, unfixable []Finding) error {
+	fmt.Fprintf(w, "## Fix plan of linked vulnerabilities\n\n")
+	if len(fixes) == 0 && len(unfixable) == 0 {
+		fmt.Fprintf(w, "No vulnerable package is linked into the application.\n")
+		return nil
+	}
+	if len(fixes) > 0 {
+		fmt.Fprintf(w, "| Upgrade | Semver major | Severity | Advisories | Fixes |\n")
+		fmt.Fprintf(w, "|---------|--------------|----------|------------|-------|\n")
+		for _, fix := range fixes {
+			upgrade := fmt.Sprintf("`%s`", fix.Name)
+			if fix.Version != "" {
+				upgrade = fmt.Sprintf("`%s@%s`", fix.Name, fix.Version)
+			}
+			major := ""
+			if fix.IsSemVerMajor {
+				major = "yes"
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %d | %s |\n", upgrade, major, fix.Severity, len(fix.AdvisoryIDs), escapeMarkdown(strings.Join(linkedPackages(fix.Findings), ", ")))
+		}
+	}
+	if len(unfixable) > 0 {
+		fmt.Fprintf(w, "\nNo fix is available for %s.\n", escapeMarkdown(strings.Join(linkedPackages(unfixable), ", ")))
+	}
+	return nil
+}
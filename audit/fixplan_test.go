@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+func fixPlanFindings() []Finding {
+	advisory := func(id, severity string) Advisory {
+		return Advisory{Via: npmaudit.Via{URL: "https://github.com/advisories/" + id, Severity: severity}, Path: []string{"x"}}
+	}
+	webpack5 := &npmaudit.FixAvailable{Name: "webpack", Version: "5.0.0", IsSemVerMajor: true}
+	return []Finding{
+		{
+			Module:        linkedpackage.Module{Name: "lodash", Version: "4.17.15"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "high", FixAvailable: &npmaudit.FixAvailable{}},
+			Advisories:    []Advisory{advisory("GHSA-p6mc-m468-83gw", "high")},
+		},
+		{
+			Module:        linkedpackage.Module{Name: "serialize-javascript", Version: "2.1.0"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "high", FixAvailable: webpack5},
+			Advisories:    []Advisory{advisory("GHSA-hxcc-f52p-wc94", "high")},
+		},
+		{
+			Module:        linkedpackage.Module{Name: "terser", Version: "4.0.0"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "moderate", FixAvailable: webpack5},
+			Advisories:    []Advisory{advisory("GHSA-4wf5-vphf-c2xc", "moderate"), advisory("GHSA-hxcc-f52p-wc94", "high")},
+		},
+		{
+			Module:        linkedpackage.Module{Name: "request", Version: "2.88.2"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "moderate"},
+			Advisories:    []Advisory{advisory("GHSA-p8p7-x288-28g6", "moderate")},
+		},
+		{
+			Module:        linkedpackage.Module{Name: "minimist", Version: "0.0.8"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "low", FixAvailable: &npmaudit.FixAvailable{}},
+			Advisories:    []Advisory{{Via: npmaudit.Via{URL: "https://github.com/advisories/GHSA-vh95-rmgr-6w4m"}, Ignored: &Ignore{Reason: "not used"}}},
+		},
+	}
+}
+
+func TestFixPlan(t *testing.T) {
+	fixes, unfixable := FixPlan(fixPlanFindings())
+	type fix struct {
+		upgrade    string
+		severity   string
+		advisories []string
+		packages   []string
+	}
+	var got []fix
+	for _, f := range fixes {
+		got = append(got, fix{f.String(), f.Severity, f.AdvisoryIDs, linkedPackages(f.Findings)})
+	}
+	assert.Equal(t, []fix{
+		{"webpack@5.0.0 (semver major)", "high", []string{"GHSA-4wf5-vphf-c2xc", "GHSA-hxcc-f52p-wc94"}, []string{"serialize-javascript@2.1.0", "terser@4.0.0"}},
+		{"lodash (compatible update)", "high", []string{"GHSA-p6mc-m468-83gw"}, []string{"lodash@4.17.15"}},
+	}, got)
+	assert.Equal(t, []string{"request@2.88.2"}, linkedPackages(unfixable))
+}
+
+func TestFixPlan_ignoredSeverity(t *testing.T) {
+	fixes, _ := FixPlan([]Finding{
+		{
+			Module:        linkedpackage.Module{Name: "json5", Version: "1.0.1"},
+			Vulnerability: npmaudit.Vulnerability{Severity: "critical", FixAvailable: &npmaudit.FixAvailable{}},
+			Advisories: []Advisory{
+				{Via: npmaudit.Via{URL: "https://github.com/advisories/GHSA-9c47-m6qq-7p4h", Severity: "critical"}, Path: []string{"json5"}, Ignored: &Ignore{Reason: "not used"}},
+				{Via: npmaudit.Via{URL: "https://github.com/advisories/GHSA-xxxx-json5-low", Severity: "low"}, Path: []string{"json5"}},
+			},
+		},
+	})
+	if assert.Len(t, fixes, 1) {
+		assert.Equal(t, "low", fixes[0].Severity)
+		assert.Equal(t, []string{"GHSA-xxxx-json5-low"}, fixes[0].AdvisoryIDs)
+	}
+}
+
+func TestWriteFixPlan(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteFixPlan(&buf, FormatPlain, fixPlanFindings()))
+	assert.Equal(t, `------
+[high] webpack@5.0.0 (semver major)
+    clears 2 linked advisories: GHSA-4wf5-vphf-c2xc, GHSA-hxcc-f52p-wc94
+    fixes: serialize-javascript@2.1.0, terser@4.0.0
+------
+[high] lodash (compatible update)
+    clears 1 linked advisories: GHSA-p6mc-m468-83gw
+    fixes: lodash@4.17.15
+------
+no fix available: request@2.88.2
+======
+2 upgrades (1 semver major) fix linked vulnerabilities, 1 vulnerable packages have no fix
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteFixPlan(&buf, FormatMarkdown, fixPlanFindings()))
+	assert.Equal(t, "## Fix plan of linked vulnerabilities\n\n"+
+		"| Upgrade | Semver major | Severity | Advisories | Fixes |\n"+
+		"|---------|--------------|----------|------------|-------|\n"+
+		"| `webpack@5.0.0` | yes | high | 2 | serialize-javascript@2.1.0, terser@4.0.0 |\n"+
+		"| `lodash` |  | high | 1 | lodash@4.17.15 |\n"+
+		"\nNo fix is available for request@2.88.2.\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteFixPlan(&buf, FormatJSON, fixPlanFindings()))
+	var plan jsonFixPlan
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &plan))
+	assert.Len(t, plan.Fixes, 2)
+	assert.True(t, plan.Fixes[0].IsSemVerMajor)
+	assert.Equal(t, "5.0.0", plan.Fixes[0].Version)
+	assert.Len(t, plan.Fixes[0].Modules, 2)
+	assert.Equal(t, "request", plan.Unfixable[0].Name)
+
+	assert.Error(t, WriteFixPlan(&buf, FormatSARIF, fixPlanFindings()))
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatJSON     = "json"
+	FormatSARIF    = "sarif"
+	FormatMarkdown = "markdown"
+	FormatOpenVEX  = "openvex"
+)
+
+// Severities are npm audit's severity names from the most severe one.
+var Severities = []string{"critical", "high", "moderate", "low", "info"}
+
+// Formats is the list of supported output formats.
+var Formats = []string{FormatPlain, FormatJSON, FormatSARIF, FormatMarkdown, FormatOpenVEX}
+
+type Options struct {
+	// ManifestPath is the path of package.json that is used as SARIF's result location
+	ManifestPath string
+	// Report is the audit report that findings come from. OpenVEX format uses it to describe
+	// vulnerable packages that are installed but not linked
+	Report *npmaudit.AuditReport
+	// Modules are the linked modules that findings come from. OpenVEX format uses them to tell vulnerable packages
+	// whose linked copies are other versions from vulnerable packages that are not linked
+	Modules []linkedpackage.Module
+	// ProductID is the purl of the application that is used as OpenVEX's product
+	ProductID string
+	// Author is OpenVEX document's author. "linkedpackage" is used if it is empty
+	Author string
+	// Created is OpenVEX document's timestamp. Current time is used if it is zero
+	Created time.Time
+}
+
+// Write exports findings in the format.
+func Write(w io.Writer, format string, findings []Finding, opt Options) error {
+	switch format {
+	case FormatPlain:
+		return writePlain(w, findings)
+	case FormatJSON:
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeSARIF(w, findings, opt)
+	case FormatMarkdown:
+		return writeMarkdown(w, findings)
+	case FormatOpenVEX:
+		return writeOpenVEX(w, findings, opt)
+	}
+	return fmt.Errorf("unknown audit format: %s", format)
+}
+
+func writePlain(w io.Writer, findings []Finding) error {
+	suppressed := 0
+	for _, f := range findings {
+		if f.Suppressed() {
+			suppressed++
+			continue
+		}
+		fmt.Fprintf(w, "------\n")
+		fmt.Fprintf(w, "[%s] %s: %s\n", f.Severity(), f.Module.Name, f.Module.Version)
+		first := true
+		for _, a := range f.Advisories {
+			if a.Ignored != nil {
+				continue
+			}
+			if !first {
+				fmt.Fprintf(w, "    ------\n")
+			}
+			first = false
+			fmt.Fprintf(w, "    [%s] %s @ %s\n", a.Severity, a.Name, a.Range)
+			fmt.Fprintf(w, "    %s\n", a.Title)
+			fmt.Fprintf(w, "    %s\n", a.URL)
+			if a.Transitive() {
+				fmt.Fprintf(w, "    path: %s\n", a.PathString())
+			}
+		}
+		for _, cycle := range f.Cycles {
+			fmt.Fprintf(w, "    dependency cycle: %s\n", strings.Join(cycle, " > "))
+		}
+	}
+	summary := Summarize(findings)
+	fmt.Fprintf(w, "======\n")
+	fmt.Fprintf(w, "%d vulnerable packages are linked", summary.Total)
+	if summary.Total > 0 {
+		fmt.Fprintf(w, " (%s)", summaryText(summary))
+	}
+	if suppressed > 0 {
+		fmt.Fprintf(w, ", %d are suppressed by the ignore list", suppressed)
+	}
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// summaryText returns text like "1 critical, 2 low".
+func summaryText(summary npmaudit.Vulnerabilities) string {
+	counts := map[string]int{
+		"critical": summary.Critical,
+		"high":     summary.High,
+		"moderate": summary.Moderate,
+		"low":      summary.Low,
+		"info":     summary.Info,
+	}
+	var result []string
+	for _, severity := range Severities {
+		if counts[severity] > 0 {
+			result = append(result, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	findings := Match(testModules, testReport)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatPlain,
+			want: `------
+[high] lodash: 4.17.15
+    [high] lodash @ <4.17.19
+    Prototype Pollution
+    https://github.com/advisories/GHSA-p6mc-m468-83gw
+======
+1 vulnerable packages are linked (1 high)
+`,
+		},
+		{
+			format: FormatMarkdown,
+			want: "## Vulnerabilities in linked packages\n\n" +
+				"1 vulnerable packages are linked (1 high).\n\n" +
+				"| Severity | Package | Advisory | Vulnerable range | Path |\n" +
+				"|----------|---------|----------|------------------|------|\n" +
+				"| high | `lodash@4.17.15` | [Prototype Pollution](https://github.com/advisories/GHSA-p6mc-m468-83gw) | <4.17.19 | lodash |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Write(&buf, tt.format, findings, Options{}))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatJSON, Match(testModules, testReport), Options{}))
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Len(t, report.Findings, 1)
+	assert.Equal(t, jsonModule{
+		Name:    "lodash",
+		Version: "4.17.15",
+		Path:    "/node_modules/lodash",
+		PURL:    "pkg:npm/lodash@4.17.15",
+	}, report.Findings[0].Module)
+	assert.Equal(t, []jsonAdvisory{
+		{
+			ID:       "GHSA-p6mc-m468-83gw",
+			Name:     "lodash",
+			Title:    "Prototype Pollution",
+			URL:      "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+			Severity: "high",
+			Range:    "<4.17.19",
+			Path:     []string{"lodash"},
+		},
+	}, report.Findings[0].Advisories)
+	assert.Equal(t, 1, report.Summary.High)
+	assert.Equal(t, 1, report.Summary.Total)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatSARIF, Match(testModules, testReport), Options{ManifestPath: "frontend/package.json"}))
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 1)
+	assert.Equal(t, "GHSA-p6mc-m468-83gw", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "8.0", run.Tool.Driver.Rules[0].Properties.SecuritySeverity)
+	assert.Len(t, run.Results, 1)
+	assert.Equal(t, "GHSA-p6mc-m468-83gw", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "frontend/package.json", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestWrite_unknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Write(&buf, "xml", nil, Options{}))
+}
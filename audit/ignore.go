@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Ignore is the entry of the ignore list. Advisories that don't affect the application can be suppressed
+// with the reason until the expiry date.
+type Ignore struct {
+	// Advisory is GHSA ID (or npm advisory ID like "NPM-1523") or advisory URL
+	Advisory string `json:"advisory"`
+	// Package is the name of the linked package or the vulnerable package
+	Package string `json:"package"`
+	Reason  string `json:"reason"`
+	// Expires is the last date (YYYY-MM-DD) that the entry is effective
+	Expires string `json:"expires"`
+
+	expires time.Time
+}
+
+// Expired returns true if the entry is not effective at the time.
+func (i Ignore) Expired(now time.Time) bool {
+	return !now.Before(i.expires.AddDate(0, 0, 1))
+}
+
+func (i Ignore) matches(f Finding, a Advisory) bool {
+	if i.Advisory != AdvisoryID(a.Via) && i.Advisory != a.URL {
+		return false
+	}
+	return i.Package == f.Module.Name || i.Package == a.Name
+}
+
+func (i Ignore) String() string {
+	return fmt.Sprintf("%s (%s) expires at %s: %s", i.Advisory, i.Package, i.Expires, i.Reason)
+}
+
+type IgnoreList struct {
+	Ignores []Ignore `json:"ignores"`
+}
+
+// LoadIgnoreList reads the ignore list JSON file and validates the entries.
+func LoadIgnoreList(path string) (*IgnoreList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var result IgnoreList
+	if err := json.NewDecoder(f).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var errs []string
+	for i := range result.Ignores {
+		ignore := &result.Ignores[i]
+		var missing []string
+		if ignore.Advisory == "" {
+			missing = append(missing, "advisory")
+		}
+		if ignore.Package == "" {
+			missing = append(missing, "package")
+		}
+		if strings.TrimSpace(ignore.Reason) == "" {
+			missing = append(missing, "reason")
+		}
+		if ignore.Expires == "" {
+			missing = append(missing, "expires")
+		} else if ignore.expires, err = time.Parse("2006-01-02", ignore.Expires); err != nil {
+			errs = append(errs, fmt.Sprintf("ignores[%d]: invalid expires %q (YYYY-MM-DD is required)", i, ignore.Expires))
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Sprintf("ignores[%d]: %s is required", i, strings.Join(missing, ", ")))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %w", path, errors.New(strings.Join(errs, "; ")))
+	}
+	return &result, nil
+}
+
+// Apply marks advisories that match effective entries as suppressed.
+// Expired entries are not applied and all of them are returned as the second result, even if they don't match
+// any findings, so that stale entries are reviewed.
+func (l *IgnoreList) Apply(findings []Finding, now time.Time) ([]Finding, []Ignore) {
+	var expired []Ignore
+	for _, ignore := range l.Ignores {
+		if ignore.Expired(now) {
+			expired = append(expired, ignore)
+		}
+	}
+	result := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		advisories := make([]Advisory, len(f.Advisories))
+		for i, a := range f.Advisories {
+			for _, ignore := range l.Ignores {
+				if !ignore.matches(f, a) || ignore.Expired(now) {
+					continue
+				}
+				ignore := ignore
+				a.Ignored = &ignore
+				break
+			}
+			advisories[i] = a
+		}
+		f.Advisories = advisories
+		result = append(result, f)
+	}
+	return result, expired
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadIgnoreList(t *testing.T) {
+	list, err := LoadIgnoreList("../testdata/audit-ignore/ignore.json")
+	assert.NoError(t, err)
+	assert.Len(t, list.Ignores, 2)
+	assert.Equal(t, "lodash", list.Ignores[0].Package)
+
+	_, err = LoadIgnoreList("../testdata/audit-ignore/invalid.json")
+	assert.EqualError(t, err, `../testdata/audit-ignore/invalid.json: ignores[0]: invalid expires "2030/12/31" (YYYY-MM-DD is required); ignores[0]: reason is required`)
+}
+
+func TestIgnore_Expired(t *testing.T) {
+	list, err := LoadIgnoreList("../testdata/audit-ignore/ignore.json")
+	assert.NoError(t, err)
+	ignore := list.Ignores[1]
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "before expiry",
+			now:  time.Date(2021, 1, 30, 0, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "last day",
+			now:  time.Date(2021, 1, 31, 23, 59, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "after expiry",
+			now:  time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ignore.Expired(tt.now))
+		})
+	}
+}
+
+func TestIgnoreList_Apply(t *testing.T) {
+	list, err := LoadIgnoreList("../testdata/audit-ignore/ignore.json")
+	assert.NoError(t, err)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	findings, expired := list.Apply(Match(testModules, testReport), now)
+	// the expired entry of json5 doesn't match the findings but it is reported
+	assert.Equal(t, []Ignore{list.Ignores[1]}, expired)
+	assert.Len(t, findings, 1)
+	assert.True(t, findings[0].Suppressed())
+	assert.Equal(t, "zipObjectDeep is not used in the application", findings[0].Advisories[0].Ignored.Reason)
+	assert.False(t, Exceeds(findings, "low"))
+	assert.Equal(t, 0, Summarize(findings).Total)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatPlain, findings, Options{}))
+	assert.Equal(t, "======\n0 vulnerable packages are linked, 1 are suppressed by the ignore list\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, FormatJSON, findings, Options{}))
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.True(t, report.Findings[0].Suppressed)
+	assert.Equal(t, &jsonSuppression{
+		Reason:  "zipObjectDeep is not used in the application",
+		Expires: "2030-12-31",
+	}, report.Findings[0].Advisories[0].Suppressed)
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, FormatSARIF, findings, Options{}))
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, []sarifSuppression{
+		{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: "zipObjectDeep is not used in the application",
+		},
+	}, log.Runs[0].Results[0].Suppressions)
+}
+
+func TestIgnoreList_Apply_expired(t *testing.T) {
+	list := &IgnoreList{
+		Ignores: []Ignore{
+			{
+				Advisory: "GHSA-p6mc-m468-83gw",
+				Package:  "lodash",
+				Reason:   "not used",
+				Expires:  "2020-01-01",
+				expires:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				// the advisory doesn't appear any more
+				Advisory: "GHSA-xxxx-xxxx-xxxx",
+				Package:  "left-pad",
+				Reason:   "fixed upstream",
+				Expires:  "2021-01-01",
+				expires:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				Advisory: "GHSA-yyyy-yyyy-yyyy",
+				Package:  "vue",
+				Reason:   "still effective",
+				Expires:  "2099-01-01",
+				expires:  time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	findings, expired := list.Apply(Match(testModules, testReport), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, list.Ignores[:2], expired)
+	assert.False(t, findings[0].Suppressed())
+	assert.True(t, Exceeds(findings, "high"))
+}
+
+func TestWrite_partiallyIgnored(t *testing.T) {
+	criticalVia := lodashVia
+	criticalVia.Source = 1065
+	criticalVia.Title = "Command Injection"
+	criticalVia.URL = "https://github.com/advisories/GHSA-35jh-r3h4-6jhm"
+	criticalVia.Severity = "critical"
+	findings := []Finding{
+		{
+			Module:        testModules[1],
+			Vulnerability: npmaudit.Vulnerability{Name: "lodash", Severity: "critical", Range: "<4.17.21"},
+			Advisories: []Advisory{
+				{Via: criticalVia, Path: []string{"lodash"}, Ignored: &Ignore{Reason: "template is not used"}},
+				{Via: lodashVia, Path: []string{"lodash"}},
+			},
+		},
+	}
+	assert.False(t, Exceeds(findings, "critical"))
+
+	// displayed severity is the same as the summary and the threshold
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatPlain, findings, Options{}))
+	assert.Contains(t, buf.String(), "[high] lodash: 4.17.15\n")
+	assert.NotContains(t, buf.String(), "critical")
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, FormatJSON, findings, Options{}))
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "high", report.Findings[0].Severity)
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+)
+
+type jsonReport struct {
+	Findings []jsonFinding            `json:"findings"`
+	Summary  npmaudit.Vulnerabilities `json:"summary"`
+}
+
+type jsonFinding struct {
+	Module       jsonModule             `json:"module"`
+	Severity     string                 `json:"severity"`
+	Range        string                 `json:"range"`
+	Nodes        []string               `json:"nodes"`
+	Advisories   []jsonAdvisory         `json:"advisories"`
+	CausedBy     []string               `json:"causedBy,omitempty"`
+	Suppressed   bool                   `json:"suppressed"`
+	Cycles       [][]string             `json:"cycles,omitempty"`
+	FixAvailable *npmaudit.FixAvailable `json:"fixAvailable,omitempty"`
+}
+
+type jsonModule struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	PURL    string `json:"purl,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+type jsonAdvisory struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	URL      string   `json:"url"`
+	Severity string   `json:"severity"`
+	Range    string   `json:"range"`
+	Path     []string `json:"path"`
+	// Suppressed is the ignore list entry that suppresses the advisory
+	Suppressed *jsonSuppression `json:"suppressed,omitempty"`
+}
+
+type jsonSuppression struct {
+	Reason  string `json:"reason"`
+	Expires string `json:"expires"`
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	report := jsonReport{
+		Findings: []jsonFinding{},
+		Summary:  Summarize(findings),
+	}
+	for _, f := range findings {
+		v := f.Vulnerability
+		// severity of suppressed findings is the vulnerability's one because all advisories are ignored
+		severity := v.Severity
+		if !f.Suppressed() {
+			severity = f.Severity()
+		}
+		jf := jsonFinding{
+			Module:       newJSONModule(f),
+			Severity:     severity,
+			Range:        v.Range,
+			Nodes:        v.Nodes,
+			Advisories:   []jsonAdvisory{},
+			CausedBy:     v.CausedBy,
+			Cycles:       f.Cycles,
+			Suppressed:   f.Suppressed(),
+			FixAvailable: v.FixAvailable,
+		}
+		for _, a := range f.Advisories {
+			ja := jsonAdvisory{
+				ID:       AdvisoryID(a.Via),
+				Name:     a.Name,
+				Title:    a.Title,
+				URL:      a.URL,
+				Severity: a.Severity,
+				Range:    a.Range,
+				Path:     a.Path,
+			}
+			if a.Ignored != nil {
+				ja.Suppressed = &jsonSuppression{
+					Reason:  a.Ignored.Reason,
+					Expires: a.Ignored.Expires,
+				}
+			}
+			jf.Advisories = append(jf.Advisories, ja)
+		}
+		report.Findings = append(report.Findings, jf)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func writeMarkdown(w io.Writer, findings []Finding) error {
+	fmt.Fprintf(w, "## Vulnerabilities in linked packages\n\n")
+	summary := Summarize(findings)
+	suppressed := len(findings) - summary.Total
+	if summary.Total == 0 {
+		fmt.Fprintf(w, "No vulnerable package is linked into the application.\n")
+	} else {
+		fmt.Fprintf(w, "%d vulnerable packages are linked (%s).\n\n", summary.Total, summaryText(summary))
+		fmt.Fprintf(w, "| Severity | Package | Advisory | Vulnerable range | Path |\n")
+		fmt.Fprintf(w, "|----------|---------|----------|------------------|------|\n")
+		for _, f := range findings {
+			if f.Suppressed() {
+				continue
+			}
+			v := f.Vulnerability
+			pkg := fmt.Sprintf("`%s@%s`", f.Module.Name, f.Module.Version)
+			if len(f.Advisories) == 0 {
+				fmt.Fprintf(w, "| %s | %s | via %s | %s | |\n", v.Severity, pkg, escapeMarkdown(strings.Join(v.CausedBy, ", ")), escapeMarkdown(v.Range))
+				continue
+			}
+			for _, a := range f.Advisories {
+				if a.Ignored != nil {
+					continue
+				}
+				fmt.Fprintf(w, "| %s | %s | [%s](%s) | %s | %s |\n", a.Severity, pkg, escapeMarkdown(a.Title), a.URL, escapeMarkdown(a.Range), escapeMarkdown(a.PathString()))
+			}
+		}
+	}
+	if suppressed > 0 {
+		fmt.Fprintf(w, "\n%d vulnerable packages are suppressed by the ignore list.\n", suppressed)
+	}
+	return nil
+}
+
+func escapeMarkdown(src string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(src)
+}
@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+)
+
+const (
+	openVEXContext = "https://openvex.dev/ns/v0.2.0"
+	openVEXIDBase  = "https://openvex.dev/docs/public/vex-"
+)
+
+// OpenVEX statuses and justification that this package uses
+const (
+	vexStatusAffected           = "affected"
+	vexStatusNotAffected        = "not_affected"
+	vexVulnerableCodeNotPresent = "vulnerable_code_not_present"
+)
+
+type vexDocument struct {
+	Context    string         `json:"@context"`
+	ID         string         `json:"@id"`
+	Author     string         `json:"author"`
+	Timestamp  string         `json:"timestamp"`
+	Version    int            `json:"version"`
+	Tooling    string         `json:"tooling"`
+	Statements []vexStatement `json:"statements"`
+}
+
+type vexStatement struct {
+	Vulnerability   vexVulnerability `json:"vulnerability"`
+	Products        []vexProduct     `json:"products"`
+	Status          string           `json:"status"`
+	Justification   string           `json:"justification,omitempty"`
+	ImpactStatement string           `json:"impact_statement,omitempty"`
+	ActionStatement string           `json:"action_statement,omitempty"`
+}
+
+type vexVulnerability struct {
+	ID          string `json:"@id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type vexProduct struct {
+	ID            string         `json:"@id"`
+	Subcomponents []vexComponent `json:"subcomponents,omitempty"`
+}
+
+type vexComponent struct {
+	ID string `json:"@id"`
+}
+
+// vexLinked is the linked copies of the vulnerable package.
+type vexLinked struct {
+	purls   []string
+	ignored []*Ignore
+}
+
+// writeOpenVEX exports statements for all vulnerable packages in the audit report.
+// Vulnerable packages that are linked are "affected". Others are "not_affected" with "vulnerable_code_not_present",
+// and the impact statement tells whether the linked copies are versions out of the vulnerable range or the package
+// is installed but not linked.
+func writeOpenVEX(w io.Writer, findings []Finding, opt Options) error {
+	if opt.Report == nil {
+		return errors.New("OpenVEX requires the whole audit report")
+	}
+	linked := map[string]*vexLinked{}
+	for _, f := range findings {
+		for _, a := range f.Advisories {
+			if a.Transitive() {
+				// the vulnerable package itself has its own finding if it is linked
+				continue
+			}
+			key := AdvisoryID(a.Via) + "\x00" + a.Name
+			l, ok := linked[key]
+			if !ok {
+				l = &vexLinked{}
+				linked[key] = l
+			}
+			l.purls = append(l.purls, f.Module.PURL())
+			l.ignored = append(l.ignored, a.Ignored)
+		}
+	}
+
+	// linked copies of the packages that don't have findings
+	others := map[string][]string{}
+	for _, m := range opt.Modules {
+		key := npmaudit.VulnerabilityKey(m.Lang, m.Name)
+		others[key] = append(others[key], m.PURL())
+	}
+
+	statements := []vexStatement{}
+	used := map[string]bool{}
+	for _, v := range opt.Report.Vulnerabilities {
+		for _, c := range v.Cause {
+			id := AdvisoryID(c)
+			key := id + "\x00" + c.Name
+			if used[key] {
+				continue
+			}
+			used[key] = true
+			statement := vexStatement{
+				Vulnerability: vexVulnerability{
+					ID:          c.URL,
+					Name:        id,
+					Description: c.Title,
+				},
+			}
+			if l, ok := linked[key]; ok {
+				statement.Products = vexProducts(opt.ProductID, l.purls)
+				if reason := ignoredReason(l.ignored); reason != "" {
+					statement.Status = vexStatusNotAffected
+					statement.ImpactStatement = reason
+				} else {
+					statement.Status = vexStatusAffected
+					statement.ActionStatement = fmt.Sprintf("Update %s to a version that is not in %s", c.Name, c.Range)
+				}
+			} else {
+				m := linkedpackage.Module{Lang: "js", Name: c.Name}
+				if !v.JS() {
+					m.Lang = v.Lang
+				}
+				statement.Status = vexStatusNotAffected
+				statement.Justification = vexVulnerableCodeNotPresent
+				if purls, ok := others[npmaudit.VulnerabilityKey(m.Lang, m.Name)]; ok {
+					statement.Products = vexProducts(opt.ProductID, purls)
+					statement.ImpactStatement = fmt.Sprintf("The linked versions of %s are not in %s", c.Name, c.Range)
+				} else {
+					statement.Products = vexProducts(opt.ProductID, []string{m.PURL()})
+					statement.ImpactStatement = fmt.Sprintf("%s is installed but it is not linked into the application", c.Name)
+				}
+			}
+			statements = append(statements, statement)
+		}
+	}
+	sort.Slice(statements, func(i, j int) bool {
+		if statements[i].Vulnerability.Name != statements[j].Vulnerability.Name {
+			return statements[i].Vulnerability.Name < statements[j].Vulnerability.Name
+		}
+		return statements[i].Products[0].ID < statements[j].Products[0].ID
+	})
+
+	author := opt.Author
+	if author == "" {
+		author = "linkedpackage"
+	}
+	created := opt.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	doc := vexDocument{
+		Context:    openVEXContext,
+		Author:     author,
+		Timestamp:  created.UTC().Format(time.RFC3339),
+		Version:    1,
+		Tooling:    "linkedpackage",
+		Statements: statements,
+	}
+	// the document ID is derived from the content like vexctl does
+	var content bytes.Buffer
+	if err := json.NewEncoder(&content).Encode(doc); err != nil {
+		return err
+	}
+	doc.ID = fmt.Sprintf("%s%x", openVEXIDBase, sha256.Sum256(content.Bytes()))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// vexProducts returns the application product that contains the packages as subcomponents.
+// Packages become products themselves if the application's ID is unknown.
+func vexProducts(productID string, purls []string) []vexProduct {
+	purls = uniqueStrings(purls)
+	if productID == "" {
+		var result []vexProduct
+		for _, purl := range purls {
+			result = append(result, vexProduct{ID: purl})
+		}
+		return result
+	}
+	product := vexProduct{ID: productID}
+	for _, purl := range purls {
+		product.Subcomponents = append(product.Subcomponents, vexComponent{ID: purl})
+	}
+	return []vexProduct{product}
+}
+
+// ignoredReason returns the reason if all linked copies are suppressed by the ignore list.
+func ignoredReason(ignores []*Ignore) string {
+	for _, ignore := range ignores {
+		if ignore == nil {
+			return ""
+		}
+	}
+	return ignores[0].Reason
+}
+
+func uniqueStrings(values []string) []string {
+	var result []string
+	used := map[string]bool{}
+	for _, v := range values {
+		if !used[v] {
+			used[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteOpenVEX(t *testing.T) {
+	minimistVia := npmaudit.Via{
+		Source:   1179,
+		Name:     "minimist",
+		Title:    "Prototype Pollution",
+		URL:      "https://github.com/advisories/GHSA-vh95-rmgr-6w4m",
+		Severity: "moderate",
+		Range:    "<0.2.1",
+	}
+	vueVia := npmaudit.Via{
+		Source:   1005,
+		Name:     "vue",
+		Title:    "ReDoS vulnerability in vue package",
+		URL:      "https://github.com/advisories/GHSA-5j4c-8p2g-v4jx",
+		Severity: "low",
+		Range:    "<2.5.17",
+	}
+	report := &npmaudit.AuditReport{
+		AuditReportVersion: 2,
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"lodash":   testReport.Vulnerabilities["lodash"],
+			"minimist": {Name: "minimist", Severity: "moderate", Range: "<0.2.1", Nodes: []string{"node_modules/minimist"}, Cause: []npmaudit.Via{minimistVia}},
+			// other version of vue is installed but the linked copy is not vulnerable
+			"vue": {Name: "vue", Severity: "low", Range: "<2.5.17", Cause: []npmaudit.Via{vueVia}},
+		},
+	}
+	opt := Options{
+		Report:    report,
+		Modules:   testModules,
+		ProductID: "pkg:npm/my-app@1.0.0",
+		Created:   time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatOpenVEX, Match(testModules, report), opt))
+	var doc vexDocument
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "https://openvex.dev/ns/v0.2.0", doc.Context)
+	assert.True(t, strings.HasPrefix(doc.ID, "https://openvex.dev/docs/public/vex-"))
+	assert.Equal(t, "linkedpackage", doc.Author)
+	assert.Equal(t, "2021-04-01T00:00:00Z", doc.Timestamp)
+	assert.Equal(t, []vexStatement{
+		{
+			Vulnerability: vexVulnerability{
+				ID:          "https://github.com/advisories/GHSA-5j4c-8p2g-v4jx",
+				Name:        "GHSA-5j4c-8p2g-v4jx",
+				Description: "ReDoS vulnerability in vue package",
+			},
+			Products: []vexProduct{
+				{ID: "pkg:npm/my-app@1.0.0", Subcomponents: []vexComponent{{ID: "pkg:npm/vue@2.6.12"}}},
+			},
+			Status:          "not_affected",
+			Justification:   "vulnerable_code_not_present",
+			ImpactStatement: "The linked versions of vue are not in <2.5.17",
+		},
+		{
+			Vulnerability: vexVulnerability{
+				ID:          "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+				Name:        "GHSA-p6mc-m468-83gw",
+				Description: "Prototype Pollution",
+			},
+			Products: []vexProduct{
+				{ID: "pkg:npm/my-app@1.0.0", Subcomponents: []vexComponent{{ID: "pkg:npm/lodash@4.17.15"}}},
+			},
+			Status:          "affected",
+			ActionStatement: "Update lodash to a version that is not in <4.17.19",
+		},
+		{
+			Vulnerability: vexVulnerability{
+				ID:          "https://github.com/advisories/GHSA-vh95-rmgr-6w4m",
+				Name:        "GHSA-vh95-rmgr-6w4m",
+				Description: "Prototype Pollution",
+			},
+			Products: []vexProduct{
+				{ID: "pkg:npm/my-app@1.0.0", Subcomponents: []vexComponent{{ID: "pkg:npm/minimist"}}},
+			},
+			Status:          "not_affected",
+			Justification:   "vulnerable_code_not_present",
+			ImpactStatement: "minimist is installed but it is not linked into the application",
+		},
+	}, doc.Statements)
+
+	// the document ID depends only on the content
+	var buf2 bytes.Buffer
+	assert.NoError(t, Write(&buf2, FormatOpenVEX, Match(testModules, report), opt))
+	assert.Equal(t, buf.String(), buf2.String())
+}
+
+func TestWriteOpenVEX_ignored(t *testing.T) {
+	findings := Match(testModules, testReport)
+	findings[0].Advisories[0].Ignored = &Ignore{Reason: "zipObjectDeep is not used"}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, FormatOpenVEX, findings, Options{Report: testReport}))
+	var doc vexDocument
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Statements, 1)
+	assert.Equal(t, "not_affected", doc.Statements[0].Status)
+	assert.Equal(t, "zipObjectDeep is not used", doc.Statements[0].ImpactStatement)
+	assert.Equal(t, []vexProduct{{ID: "pkg:npm/lodash@4.17.15"}}, doc.Statements[0].Products)
+}
+
+func TestWriteOpenVEX_noReport(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Write(&buf, FormatOpenVEX, nil, Options{}))
+}
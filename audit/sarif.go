@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"name,omitempty"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	HelpURI          string              `json:"helpUri,omitempty"`
+	Help             *sarifMessage       `json:"help,omitempty"`
+	Properties       sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLevel converts npm's severity into SARIF's result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "moderate":
+		return "warning"
+	}
+	return "note"
+}
+
+// sarifSecuritySeverity is the score that GitHub code scanning uses to show severity.
+func sarifSecuritySeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "9.5"
+	case "high":
+		return "8.0"
+	case "moderate":
+		return "5.5"
+	case "low":
+		return "2.0"
+	}
+	return ""
+}
+
+func writeSARIF(w io.Writer, findings []Finding, opt Options) error {
+	manifest := opt.ManifestPath
+	if manifest == "" {
+		manifest = "package.json"
+	}
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "linkedpackage",
+				InformationURI: "https://github.com/future-architect/linkedpackage",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+	rules := map[string]sarifRule{}
+	for _, f := range findings {
+		for _, a := range f.Advisories {
+			id := AdvisoryID(a.Via)
+			if _, ok := rules[id]; !ok {
+				rules[id] = newSARIFRule(id, a.Via)
+			}
+			message := fmt.Sprintf("%s@%s is linked into the application and is vulnerable: %s (%s %s)", f.Module.Name, f.Module.Version, a.Title, a.Name, a.Range)
+			if a.Transitive() {
+				message += fmt.Sprintf(" through %s", a.PathString())
+			}
+			result := sarifResult{
+				RuleID: id,
+				Level:  sarifLevel(a.Severity),
+				Message: sarifMessage{
+					Text: message,
+				},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{
+								URI: filepath.ToSlash(manifest),
+							},
+						},
+					},
+				},
+			}
+			if a.Ignored != nil {
+				result.Suppressions = []sarifSuppression{
+					{
+						Kind:          "external",
+						Status:        "accepted",
+						Justification: a.Ignored.Reason,
+					},
+				}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+	for _, rule := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+	result := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+func newSARIFRule(id string, via npmaudit.Via) sarifRule {
+	return sarifRule{
+		ID:               id,
+		Name:             via.Name,
+		ShortDescription: sarifMessage{Text: via.Title},
+		HelpURI:          via.URL,
+		Help:             &sarifMessage{Text: fmt.Sprintf("%s\n%s (%s) is vulnerable. See %s", via.Title, via.Name, via.Range, via.URL)},
+		Properties: sarifRuleProperties{
+			Tags:             []string{"security", "vulnerability", via.Severity},
+			SecuritySeverity: sarifSecuritySeverity(via.Severity),
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+)
+
+// Advisory is the root advisory that affects the linked module directly or through its dependencies.
+type Advisory struct {
+	npmaudit.Via
+	// Path is the dependency path from the linked module to the vulnerable package.
+	// It contains only the linked module if the module itself is vulnerable.
+	Path []string
+	// Ignored is the ignore list entry that suppresses the advisory
+	Ignored *Ignore
+}
+
+// PathString returns the path like "a > b > vulnerable".
+func (a Advisory) PathString() string {
+	return strings.Join(a.Path, " > ")
+}
+
+// Transitive returns true when the advisory comes from dependencies.
+func (a Advisory) Transitive() bool {
+	return len(a.Path) > 1
+}
+
+// ResolveAdvisories walks CausedBy graph from the package and collects root advisories with the shortest dependency path.
+// The key is npmaudit.VulnerabilityKey of the package. The second result contains dependency cycles found during the walk.
+func ResolveAdvisories(report *npmaudit.AuditReport, key string) ([]Advisory, [][]string) {
+	var advisories []Advisory
+	var cycles [][]string
+	usedAdvisories := map[string]bool{}
+	v, ok := report.Vulnerabilities[key]
+	if !ok {
+		return nil, nil
+	}
+	if !v.JS() {
+		// dependency graph is available only in npm's report
+		for _, c := range v.Cause {
+			advisories = append(advisories, Advisory{Via: c, Path: []string{v.Name}})
+		}
+		return advisories, nil
+	}
+	visited := map[string]bool{key: true}
+	queue := [][]string{{key}}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		v, ok := report.Vulnerabilities[path[len(path)-1]]
+		if !ok {
+			continue
+		}
+		for _, c := range v.Cause {
+			key := c.URL + "\x00" + c.Name + "\x00" + c.Range
+			if usedAdvisories[key] {
+				continue
+			}
+			usedAdvisories[key] = true
+			advisories = append(advisories, Advisory{
+				Via:  c,
+				Path: path,
+			})
+		}
+		for _, next := range v.CausedBy {
+			if i := indexOf(path, next); i != -1 {
+				cycle := append(append([]string{}, path[i:]...), next)
+				cycles = append(cycles, cycle)
+				continue
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			queue = append(queue, append(append([]string{}, path...), next))
+		}
+	}
+	return advisories, cycles
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
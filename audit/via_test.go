@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAdvisories(t *testing.T) {
+	minimistVia := npmaudit.Via{
+		Source:   1179,
+		Name:     "minimist",
+		Title:    "Prototype Pollution",
+		URL:      "https://github.com/advisories/GHSA-vh95-rmgr-6w4m",
+		Severity: "moderate",
+		Range:    "<0.2.1",
+	}
+	report := &npmaudit.AuditReport{
+		Vulnerabilities: map[string]npmaudit.Vulnerability{
+			"app-lib": {Name: "app-lib", Severity: "moderate", CausedBy: []string{"mkdirp", "optimist"}},
+			"mkdirp":  {Name: "mkdirp", Severity: "moderate", CausedBy: []string{"minimist"}},
+			// optimist also depends on minimist. the shortest path is reported only once.
+			"optimist": {Name: "optimist", Severity: "moderate", CausedBy: []string{"minimist"}},
+			"minimist": {Name: "minimist", Severity: "moderate", Cause: []npmaudit.Via{minimistVia}},
+			// cyclic dependency
+			"cycle-a": {Name: "cycle-a", Severity: "low", CausedBy: []string{"cycle-b"}},
+			"cycle-b": {Name: "cycle-b", Severity: "low", CausedBy: []string{"cycle-a", "minimist"}},
+		},
+	}
+	tests := []struct {
+		name       string
+		pkg        string
+		want       []Advisory
+		wantCycles [][]string
+	}{
+		{
+			name: "direct",
+			pkg:  "minimist",
+			want: []Advisory{
+				{Via: minimistVia, Path: []string{"minimist"}},
+			},
+		},
+		{
+			name: "transitive",
+			pkg:  "app-lib",
+			want: []Advisory{
+				{Via: minimistVia, Path: []string{"app-lib", "mkdirp", "minimist"}},
+			},
+		},
+		{
+			name: "cycle",
+			pkg:  "cycle-a",
+			want: []Advisory{
+				{Via: minimistVia, Path: []string{"cycle-a", "cycle-b", "minimist"}},
+			},
+			wantCycles: [][]string{
+				{"cycle-a", "cycle-b", "cycle-a"},
+			},
+		},
+		{
+			name: "unknown package",
+			pkg:  "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cycles := ResolveAdvisories(report, tt.pkg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCycles, cycles)
+		})
+	}
+}
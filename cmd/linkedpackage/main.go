@@ -2,29 +2,68 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/audit"
 	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/future-architect/linkedpackage/osv"
+	"github.com/future-architect/linkedpackage/sbom"
 	"gopkg.in/alecthomas/kingpin.v2"
 	"io"
+	"io/fs"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
+// exit codes of audit command. Errors of command line arguments are also exitError
+// not to be mistaken for vulnerabilities.
+const (
+	exitVulnerable = 1
+	exitError      = 2
+)
+
 var (
 	app = kingpin.New("license", "dump linked package's information from compiled application")
 
 	jsFolders       = app.Flag("js-dist", "JavaScript application dist folder").ExistingDirs()
 	jsRoot          = app.Flag("js-root", "JavaScript project root folder").ExistingDir()
 	jsExtraPackages = app.Flag("js-extra-package", "JavaScript extra package").Strings()
+	electronAsars   = app.Flag("electron-asar", "Electron application's app.asar (app.asar.unpacked next to it is read too)").ExistingFiles()
+	goBinaries      = app.Flag("go-binary", "Go executable that has build information").ExistingFiles()
+	goRoot          = app.Flag("go-root", "Go project root folder that has vendor folder (default: modules are read from GOMODCACHE)").ExistingDir()
+	rustBinaries    = app.Flag("rust-binary", "Rust executable built by cargo auditable").ExistingFiles()
+	rustRoot        = app.Flag("rust-root", "Rust project root folder. Its Cargo.lock is used when --rust-binary doesn't have cargo auditable data").ExistingDir()
+	javaArchives    = app.Flag("java-archive", "Java jar or war file (Spring Boot fat jar or shaded jar)").ExistingFiles()
+	pythonSite      = app.Flag("python-site-packages", "Python site-packages folder of the virtualenv. All installed distributions are linked if --python-pyinstaller is not specified").ExistingDir()
+	pyInstallers    = app.Flag("python-pyinstaller", "executable built by PyInstaller from --python-site-packages").ExistingFiles()
+	dotnetDeps      = app.Flag("dotnet-deps", ".NET application's *.deps.json file").ExistingFiles()
+	nugetPackages   = app.Flag("nuget-packages", "NuGet global packages folder (default: $NUGET_PACKAGES or ~/.nuget/packages)").ExistingDir()
+	imageTar        = app.Flag("image-tar", "container image tarball created by docker save (or OCI image layout)").ExistingFile()
+
+	licenseCmd             = app.Command("license", "dump license")
+	licenseTitle           = licenseCmd.Flag("title", "report title").Default("Used OSS Licenses").String()
+	licenseDenyProprietary = licenseCmd.Flag("deny-proprietary", "exit with error when UNLICENSED (proprietary) package is linked").Bool()
 
-	licenseCmd   = app.Command("license", "dump license")
-	licenseTitle = licenseCmd.Flag("title", "report title").Default("Used OSS Licenses").String()
+	sbomCmd     = app.Command("sbom", "dump SBOM")
+	sbomFormat  = sbomCmd.Flag("sbom-format", "SBOM format").Default("spdx").Enum("spdx", "cyclonedx")
+	sbomName    = sbomCmd.Flag("name", "application name (default: name of --js-root folder)").String()
+	sbomVersion = sbomCmd.Flag("version", "application version").String()
 
-	auditCmd = app.Command("audit", "audit check")
-	auditOutputFormat = auditCmd.Flag("audit-format", "export format").Default("plain").Enum("plain", "json")
+	auditCmd          = app.Command("audit", "audit check")
+	auditOutputFormat = auditCmd.Flag("audit-format", "export format").Default(audit.FormatPlain).Enum(audit.Formats...)
+	auditAuditor      = auditCmd.Flag("auditor", "package manager that runs audit (default: detected from the lockfile in --js-root)").Default("auto").Enum(append([]string{"auto"}, npmaudit.AuditorNames...)...)
+	auditReportFile   = auditCmd.Flag("audit-report", "pre-generated audit report file (--audit-report=- reads stdin). It is used instead of running audit command").String()
+	auditTimeout      = auditCmd.Flag("audit-timeout", "timeout of audit command").Default("10s").Duration()
+	auditOSVDB        = auditCmd.Flag("osv-db", "OSV advisory database folder or zip file. It is used instead of npm audit (works offline)").ExistingFileOrDir()
+	auditIgnoreFile   = auditCmd.Flag("ignore-file", "JSON file of advisories to suppress with reason and expiry date").ExistingFile()
+	auditFixPlan      = auditCmd.Flag("fix-plan", "show upgrades that fix linked vulnerabilities instead of vulnerabilities (plain, json and markdown formats)").Bool()
+	auditFailOn       = auditCmd.Flag("fail-on", "exit with code 1 when linked package has vulnerability of this severity or higher").Enum("low", "moderate", "high", "critical")
 )
 
 func init() {
@@ -33,48 +72,153 @@ func init() {
 }
 
 func main() {
-	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	command, err := app.Parse(os.Args[1:])
+	if err != nil {
+		// kingpin.MustParse exits with 1 that is exitVulnerable
+		app.Errorf("%s, try --help", err)
+		os.Exit(exitError)
+	}
+	in := inputs{
+		jsRoot:          *jsRoot,
+		jsFolders:       *jsFolders,
+		jsExtraPackages: *jsExtraPackages,
+		electronAsars:   *electronAsars,
+		goBinaries:      *goBinaries,
+		goRoot:          *goRoot,
+		rustBinaries:    *rustBinaries,
+		rustRoot:        *rustRoot,
+		javaArchives:    *javaArchives,
+		pythonSite:      *pythonSite,
+		pyInstallers:    *pyInstallers,
+		dotnetDeps:      *dotnetDeps,
+		nugetPackages:   *nugetPackages,
+		imageTar:        *imageTar,
+	}
+	switch command {
 	case licenseCmd.FullCommand():
-		dumpLicense(*jsRoot, *jsFolders, *jsExtraPackages, *licenseTitle, os.Stdout)
+		if !dumpLicense(in, *licenseTitle, *licenseDenyProprietary, os.Stdout) {
+			os.Exit(1)
+		}
+	case sbomCmd.FullCommand():
+		if err := dumpSBOM(in, *sbomFormat, *sbomName, *sbomVersion, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
 	case auditCmd.FullCommand():
-		checkAudit(*jsRoot, *jsFolders, *jsExtraPackages, *auditOutputFormat, os.Stdout)
+		vulnerable, err := checkAudit(in, auditSource{
+			auditor:    *auditAuditor,
+			reportFile: *auditReportFile,
+			timeout:    *auditTimeout,
+			osvDB:      *auditOSVDB,
+		}, *auditIgnoreFile, *auditOutputFormat, *auditFixPlan, *auditFailOn, os.Stdout)
+		if err != nil {
+			log.Println(err)
+			os.Exit(exitError)
+		}
+		if vulnerable {
+			os.Exit(exitVulnerable)
+		}
 	}
 }
 
-func checkAudit(jsRoot string, jsFolders, jsExtraPackages []string, format string, writer io.Writer) {
-	parsedModules := readJSPackages(jsFolders, jsExtraPackages, jsRoot)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second * 10)
+// auditSource specifies where vulnerability information comes from.
+type auditSource struct {
+	auditor    string
+	reportFile string
+	timeout    time.Duration
+	osvDB      string
+}
+
+func (a auditSource) read(jsRoot string, modules []linkedpackage.Module) (*npmaudit.AuditReport, error) {
+	switch {
+	case a.reportFile == "-":
+		return npmaudit.ReadAuditReport(os.Stdin)
+	case a.reportFile != "":
+		f, err := os.Open(a.reportFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return npmaudit.ReadAuditReport(f)
+	case a.osvDB != "":
+		db, err := osv.Load(a.osvDB)
+		if err != nil {
+			return nil, err
+		}
+		return db.Audit(modules), nil
+	}
+	auditor := npmaudit.DetectAuditor(jsRoot)
+	if a.auditor != "auto" {
+		var err error
+		auditor, err = npmaudit.NewAuditor(a.auditor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
 	defer cancel()
-	auditReports, err := npmaudit.ExecNpmAudit(ctx, jsRoot)
+	report, err := auditor.Audit(ctx, jsRoot)
 	if err != nil {
-		log.Fatal(err)
-	}
-	audits := map[string]npmaudit.Vulnerability{}
-	for _, r := range auditReports.Vulnerabilities {
-		/*if len(r.Cause) == 0 {
-			continue
-		}*/
-		audits[r.Name] = r
-	}
-	for _, m := range parsedModules {
-		v, ok := audits[m.Name]
-		if ok {
-			fmt.Printf("------\n")
-			fmt.Printf("[%s] %s: %s\n", v.Severity, m.Name, m.Version)
-			for i, c := range v.Cause {
-				if i != 0 {
-					fmt.Printf("    ------\n")
-				}
-				fmt.Printf("    [%s] %s @ %s\n",  c.Severity, c.Name, c.Range)
-				fmt.Printf("    %s\n", c.Title)
-				fmt.Printf("    %s\n", c.URL)
-			}
+		return nil, fmt.Errorf("%s audit: %w", auditor.Name(), err)
+	}
+	return report, nil
+}
+
+// checkAudit writes vulnerabilities of linked packages.
+// It returns true if there is vulnerability that is as severe as failOn or more,
+// or if the ignore list has expired entries (even if they don't match the current vulnerabilities).
+func checkAudit(in inputs, source auditSource, ignoreFile, format string, fixPlan bool, failOn string, writer io.Writer) (bool, error) {
+	parsedModules := in.modules()
+	auditReports, err := source.read(in.jsRoot, parsedModules)
+	if err != nil {
+		return false, err
+	}
+	findings := audit.Match(parsedModules, auditReports)
+	var expired []audit.Ignore
+	if ignoreFile != "" {
+		ignores, err := audit.LoadIgnoreList(ignoreFile)
+		if err != nil {
+			return false, err
+		}
+		findings, expired = ignores.Apply(findings, time.Now())
+		for _, ignore := range expired {
+			fmt.Fprintf(os.Stderr, "expired ignore entry: %s\n", ignore)
 		}
 	}
+	if fixPlan {
+		err = audit.WriteFixPlan(writer, format, findings)
+	} else {
+		err = audit.Write(writer, format, findings, audit.Options{
+			ManifestPath: filepath.Join(in.jsRoot, "package.json"),
+			Report:       auditReports,
+			Modules:      parsedModules,
+			ProductID:    applicationPURL(in.jsRoot),
+		})
+	}
+	if err != nil {
+		return false, err
+	}
+	return audit.Exceeds(findings, failOn) || len(expired) > 0, nil
 }
 
-func dumpLicense(jsRoot string, jsFolders, jsExtraPackages []string, title string, writer io.Writer) {
-	parsedModules := readJSPackages(jsFolders, jsExtraPackages, jsRoot)
+// applicationPURL returns the purl of the application from package.json in jsRoot.
+func applicationPURL(jsRoot string) string {
+	f, err := os.Open(filepath.Join(jsRoot, "package.json"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	var packageJSON struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(f).Decode(&packageJSON); err != nil || packageJSON.Name == "" {
+		return ""
+	}
+	return linkedpackage.Module{Lang: "js", Name: packageJSON.Name, Version: packageJSON.Version}.PURL()
+}
+
+func dumpLicense(in inputs, title string, denyProprietary bool, writer io.Writer) bool {
+	parsedModules := in.modules()
 
 	groups := linkedpackage.GroupingModulesByLicense(parsedModules)
 
@@ -87,21 +231,498 @@ func dumpLicense(jsRoot string, jsFolders, jsExtraPackages []string, title strin
 		}
 		fmt.Fprintf(writer, "## %s\n\n", strings.Join(projects, ", "))
 		fmt.Fprintf(writer, "* 作者: %s\n", group.Author)
-		fmt.Fprintf(writer, "* ライセンス: %s\n", group.License)
+		if holders := copyrightHolders(group.Modules); len(holders) > 1 || (len(holders) == 1 && holders[0] != group.Author) {
+			fmt.Fprintf(writer, "* 著作権者: %s\n", strings.Join(holders, ", "))
+		}
+		switch {
+		case group.Modules[0].Proprietary:
+			fmt.Fprintf(writer, "* ライセンス: %s (プロプライエタリ: 利用許諾なし)\n", group.License)
+		case group.Modules[0].LicenseFile != "":
+			fmt.Fprintf(writer, "* ライセンス: %s (%s を参照)\n", group.License, group.Modules[0].LicenseFile)
+		default:
+			fmt.Fprintf(writer, "* ライセンス: %s\n", group.License)
+		}
+		for _, module := range group.Modules {
+			dumpModuleMetadata(module, writer)
+		}
 		if group.Modules[0].LicenseContent != "" {
 			fmt.Fprintf(writer, "\n```\n%s\n```\n\n\n", group.Modules[0].LicenseContent)
 		} else {
 			fmt.Fprintf(writer, "\n\n")
 		}
 	}
+
+	proprietaries := linkedpackage.ProprietaryModules(parsedModules)
+	for _, module := range proprietaries {
+		fmt.Fprintf(os.Stderr, "%s@%s is UNLICENSED (proprietary)\n", module.Name, module.Version)
+	}
+	return !denyProprietary || len(proprietaries) == 0
+}
+
+func dumpSBOM(in inputs, format, name, version string, writer io.Writer) error {
+	in.dirHash = true
+	parsedModules := in.modules()
+	if name == "" {
+		abs, err := filepath.Abs(in.jsRoot)
+		if err != nil {
+			return err
+		}
+		name = filepath.Base(abs)
+	}
+	opt := sbom.Options{
+		Name:    name,
+		Version: version,
+	}
+	switch format {
+	case "cyclonedx":
+		return sbom.WriteCycloneDX(writer, parsedModules, opt)
+	default:
+		return sbom.WriteSPDX(writer, parsedModules, opt)
+	}
+}
+
+var assetTypeLabels = map[string]string{
+	linkedpackage.AssetTypeStyle: "スタイルシート",
+	linkedpackage.AssetTypeFont:  "フォント",
+	linkedpackage.AssetTypeImage: "画像",
+}
+
+func dumpModuleMetadata(module linkedpackage.Module, writer io.Writer) {
+	var lines []string
+	if module.Description != "" {
+		lines = append(lines, fmt.Sprintf("    * 説明: %s\n", module.Description))
+	}
+	if module.Homepage != "" {
+		lines = append(lines, fmt.Sprintf("    * ホームページ: %s\n", module.Homepage))
+	}
+	if module.Repository != "" {
+		lines = append(lines, fmt.Sprintf("    * リポジトリ: %s\n", module.Repository))
+	}
+	if module.Bugs != "" {
+		lines = append(lines, fmt.Sprintf("    * 不具合報告: %s\n", module.Bugs))
+	}
+	if len(module.AssetTypes) > 0 {
+		var assets []string
+		for _, assetType := range module.AssetTypes {
+			assets = append(assets, assetTypeLabels[assetType])
+		}
+		lines = append(lines, fmt.Sprintf("    * 同梱ファイル: %s\n", strings.Join(assets, ", ")))
+	}
+	for _, funding := range module.Funding {
+		if funding.Type != "" {
+			lines = append(lines, fmt.Sprintf("    * 寄付: %s (%s)\n", funding.URL, funding.Type))
+		} else {
+			lines = append(lines, fmt.Sprintf("    * 寄付: %s\n", funding.URL))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "* %s@%s\n", module.Name, module.Version)
+	for _, line := range lines {
+		io.WriteString(writer, line)
+	}
 }
 
-func readJSPackages(folders []string, extraPackages []string, root string) []linkedpackage.Module {
+func copyrightHolders(modules []linkedpackage.Module) []string {
+	var result []string
+	used := map[string]bool{}
+	for _, module := range modules {
+		for _, holder := range module.CopyrightHolders() {
+			if !used[holder] {
+				used[holder] = true
+				result = append(result, holder)
+			}
+		}
+	}
+	return result
+}
+
+// inputs are the compiled applications and their project folders.
+type inputs struct {
+	jsRoot          string
+	jsFolders       []string
+	jsExtraPackages []string
+	electronAsars   []string
+	goBinaries      []string
+	goRoot          string
+	rustBinaries    []string
+	rustRoot        string
+	javaArchives    []string
+	pythonSite      string
+	pyInstallers    []string
+	dotnetDeps      []string
+	nugetPackages   string
+	imageTar        string
+	// dirHash calculates the hash of the installed package folders for SBOM. It reads all files of the packages
+	dirHash bool
+}
+
+// location is the file or folder in the file system. Readers take locations, so the same code reads
+// the OS folders, Electron's asar archives and container images.
+type location struct {
+	fsys fs.FS
+	name string
+}
+
+// hostLocation returns the location of the OS path. The parent folder becomes the file system
+// so that messages show the file names with their folders.
+func hostLocation(p string) location {
+	if dir := filepath.Dir(p); dir != p {
+		return location{fsys: linkedpackage.DirFS(dir), name: filepath.Base(p)}
+	}
+	return location{fsys: linkedpackage.DirFS(p), name: "."}
+}
+
+func hostLocations(paths []string) []location {
+	var result []location
+	for _, p := range paths {
+		result = append(result, hostLocation(p))
+	}
+	return result
+}
+
+// join returns the location of the child.
+func (l location) join(elem ...string) location {
+	return location{fsys: l.fsys, name: path.Join(append([]string{l.name}, elem...)...)}
+}
+
+// modules returns linked modules of all inputs.
+func (in inputs) modules() []linkedpackage.Module {
+	modules := readJSPackages(hostLocations(in.jsFolders), in.jsExtraPackages, hostLocation(in.jsRoot), in.dirHash)
+	modules = append(modules, readElectronPackages(in.electronAsars, in.dirHash)...)
+	modules = append(modules, readGoPackages(hostLocations(in.goBinaries), in.goRoot)...)
+	modules = append(modules, readRustPackages(hostLocations(in.rustBinaries), in.rustRoot)...)
+	modules = append(modules, readJavaPackages(hostLocations(in.javaArchives))...)
+	if in.pythonSite != "" {
+		modules = append(modules, readPythonPackages(hostLocation(in.pythonSite), hostLocations(in.pyInstallers))...)
+	} else if len(in.pyInstallers) > 0 {
+		log.Println("--python-site-packages is required to read PyInstaller executables")
+	}
+	modules = append(modules, readDotnetPackages(hostLocations(in.dotnetDeps), in.nugetPackages)...)
+	modules = append(modules, readImagePackages(in.imageTar, in.dirHash)...)
+	return modules
+}
+
+// readElectronPackages reads bundles and node_modules in the asar archives.
+// All packages in node_modules of the archive are linked because electron-builder packs only production dependencies.
+func readElectronPackages(archives []string, dirHash bool) []linkedpackage.Module {
+	var modules []linkedpackage.Module
+	for _, archive := range archives {
+		asar, err := linkedpackage.OpenAsar(archive)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		root := location{fsys: asar, name: "."}
+		entries, err := fs.ReadDir(asar, ".")
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		var folders []location
+		for _, entry := range entries {
+			if entry.Name() != "node_modules" {
+				folders = append(folders, root.join(entry.Name()))
+			}
+		}
+		var packages []string
+		entries, _ = fs.ReadDir(asar, "node_modules")
+		for _, entry := range entries {
+			// links are aliases of other packages
+			if strings.HasPrefix(entry.Name(), ".") || entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			if !strings.HasPrefix(entry.Name(), "@") {
+				packages = append(packages, entry.Name())
+				continue
+			}
+			scoped, _ := fs.ReadDir(asar, "node_modules/"+entry.Name())
+			for _, child := range scoped {
+				packages = append(packages, entry.Name()+"/"+child.Name())
+			}
+		}
+		modules = append(modules, readJSPackages(folders, packages, root, dirHash)...)
+	}
+	return modules
+}
+
+// readGoPackages reads the modules of the binaries. root is the OS folder that has vendor folder;
+// the module cache of the OS is used if it is empty or the module isn't vendored.
+func readGoPackages(binaries []location, root string) []linkedpackage.Module {
+	var modules []linkedpackage.Module
+	for _, binary := range binaries {
+		binModules, err := linkedpackage.ParseGoBinaryFS(binary.fsys, binary.name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		modules = append(modules, binModules...)
+	}
+	modules = linkedpackage.UniqueModules(modules)
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range modules {
+		err := linkedpackage.ReadProjectData(&module, root)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules
+}
+
+// readRustPackages reads the crates of the binaries. root is the OS project folder that has Cargo.lock.
+func readRustPackages(binaries []location, root string) []linkedpackage.Module {
 	var modules []linkedpackage.Module
+	usedLockfile := false
+	for _, binary := range binaries {
+		binModules, err := linkedpackage.ParseRustBinaryFS(binary.fsys, binary.name)
+		if errors.Is(err, linkedpackage.ErrNoAuditableData) && root != "" {
+			if usedLockfile {
+				continue
+			}
+			usedLockfile = true
+			log.Printf("%s: %s. crates are read from Cargo.lock\n", binary.name, err)
+			binModules, err = linkedpackage.ParseCargoLock(filepath.Join(root, "Cargo.lock"))
+		}
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		modules = append(modules, binModules...)
+	}
+	modules = linkedpackage.UniqueModules(modules)
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range modules {
+		// crates are read from $CARGO_HOME/registry/src of the OS
+		err := linkedpackage.ReadProjectData(&module, "")
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules
+}
+
+func readJavaPackages(archives []location) []linkedpackage.Module {
+	parsedModules := []linkedpackage.Module{}
+	for _, archive := range archives {
+		modules, err := readJavaArchive(archive)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, modules...)
+	}
+	return linkedpackage.UniqueModules(parsedModules)
+}
+
+// readJavaArchive reads the modules and their licenses. The archive is opened once for all modules.
+func readJavaArchive(archive location) ([]linkedpackage.Module, error) {
+	archiveFS, err := linkedpackage.OpenZipFS(archive.fsys, archive.name)
+	if err != nil {
+		return nil, err
+	}
+	defer archiveFS.Close()
+	modules, err := linkedpackage.ParseJavaArchiveFS(archiveFS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", archive.name, err)
+	}
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range linkedpackage.UniqueModules(modules) {
+		// licenses are read from the archive that contains the module
+		err := linkedpackage.ReadProjectDataFS(&module, archiveFS, ".")
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules, nil
+}
+
+// readPythonPackages reads the distributions in site-packages. Only the ones bundled into the executables are linked
+// if executables are specified.
+func readPythonPackages(sitePackages location, executables []location) []linkedpackage.Module {
+	var modules []linkedpackage.Module
+	if len(executables) == 0 {
+		installed, err := linkedpackage.ParsePythonSitePackagesFS(sitePackages.fsys, sitePackages.name)
+		if err != nil {
+			log.Println(err)
+		}
+		modules = installed
+	}
+	for _, executable := range executables {
+		bundled, err := linkedpackage.ParsePyInstallerFS(executable.fsys, executable.name, sitePackages.fsys, sitePackages.name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		modules = append(modules, bundled...)
+	}
+	modules = linkedpackage.UniqueModules(modules)
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range modules {
+		err := linkedpackage.ReadProjectDataFS(&module, sitePackages.fsys, sitePackages.name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules
+}
+
+// readDotnetPackages reads the packages of *.deps.json. packages is the NuGet global packages folder of the OS;
+// the default one is used if it is empty.
+func readDotnetPackages(depsFiles []location, packages string) []linkedpackage.Module {
+	var modules []linkedpackage.Module
+	for _, depsFile := range depsFiles {
+		depsModules, err := linkedpackage.ParseDotnetDepsFS(depsFile.fsys, depsFile.name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		modules = append(modules, depsModules...)
+	}
+	modules = linkedpackage.UniqueModules(modules)
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range modules {
+		err := linkedpackage.ReadProjectData(&module, packages)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules
+}
+
+// readImagePackages reads OS packages and applications of all languages in the merged file system of the container image.
+func readImagePackages(imageTar string, dirHash bool) []linkedpackage.Module {
+	if imageTar == "" {
+		return nil
+	}
+	img, err := linkedpackage.OpenImage(imageTar)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	defer img.Close()
+
+	var fsys fs.FS = img
+	modules := readOSPackages(location{fsys: fsys, name: "."})
+	var goBinaries, rustBinaries, javaArchives, dotnetDeps, sitePackages, jsRoots []location
+	fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		file := location{fsys: fsys, name: name}
+		if entry.IsDir() {
+			switch entry.Name() {
+			case "site-packages", "dist-packages":
+				sitePackages = append(sitePackages, file)
+			case "node_modules":
+				if _, err := fs.Stat(fsys, path.Join(path.Dir(name), "package.json")); err == nil {
+					jsRoots = append(jsRoots, location{fsys: fsys, name: path.Dir(name)})
+				}
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		switch base := entry.Name(); {
+		case strings.HasSuffix(base, ".jar") || strings.HasSuffix(base, ".war"):
+			javaArchives = append(javaArchives, file)
+		case strings.HasSuffix(base, ".deps.json"):
+			dotnetDeps = append(dotnetDeps, file)
+		case info.Mode().Perm()&0111 != 0:
+			if _, err := linkedpackage.ParseGoBinaryFS(fsys, name); err == nil {
+				goBinaries = append(goBinaries, file)
+			} else if _, err := linkedpackage.ParseRustBinaryFS(fsys, name); err == nil {
+				rustBinaries = append(rustBinaries, file)
+			}
+		}
+		return nil
+	})
+	for _, jsRoot := range jsRoots {
+		// folders next to node_modules (e.g. dist, build) are bundled applications
+		var folders []location
+		entries, _ := fs.ReadDir(jsRoot.fsys, jsRoot.name)
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name() != "node_modules" && !strings.HasPrefix(entry.Name(), ".") {
+				folders = append(folders, jsRoot.join(entry.Name()))
+			}
+		}
+		modules = append(modules, readJSPackages(folders, nil, jsRoot, dirHash)...)
+	}
+	modules = append(modules, readGoPackages(goBinaries, "")...)
+	modules = append(modules, readRustPackages(rustBinaries, "")...)
+	modules = append(modules, readJavaPackages(javaArchives)...)
+	for _, sitePackage := range sitePackages {
+		modules = append(modules, readPythonPackages(sitePackage, nil)...)
+	}
+	modules = append(modules, readDotnetPackages(dotnetDeps, "")...)
+	return modules
+}
+
+// readOSPackages reads dpkg and apk databases of the file system.
+func readOSPackages(root location) []linkedpackage.Module {
+	distro, err := linkedpackage.ReadOSReleaseFS(root.fsys, root.name)
+	if err != nil {
+		log.Println(err)
+	}
+	var modules []linkedpackage.Module
+	dpkg := root.join("var", "lib", "dpkg")
+	databases, _ := fs.Glob(root.fsys, path.Join(dpkg.name, "status.d", "*"))
+	databases = append([]string{dpkg.join("status").name}, databases...)
+	for _, database := range databases {
+		if strings.HasSuffix(database, ".md5sums") {
+			continue
+		}
+		dpkgModules, err := linkedpackage.ParseDpkgStatusFS(root.fsys, database, distro)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Println(err)
+			}
+			continue
+		}
+		modules = append(modules, dpkgModules...)
+	}
+	apkModules, err := linkedpackage.ParseApkInstalledFS(root.fsys, root.join("lib", "apk", "db", "installed").name, distro)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println(err)
+	}
+	modules = append(modules, apkModules...)
+
+	modules = linkedpackage.UniqueModules(modules)
+	parsedModules := []linkedpackage.Module{}
+	for _, module := range modules {
+		err := linkedpackage.ReadProjectDataFS(&module, root.fsys, root.name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		parsedModules = append(parsedModules, module)
+	}
+	return parsedModules
+}
+
+// readJSPackages reads packages bundled into the folders. Their project data is read from node_modules in root.
+// dirHash calculates the hashes of the package folders too.
+func readJSPackages(folders []location, extraPackages []string, root location, dirHash bool) []linkedpackage.Module {
+	var modules []linkedpackage.Module
+	// fonts and images are compared with the files in node_modules of the root
+	var assetIndex *linkedpackage.JSAssetIndex
 	for _, folder := range folders {
-		sourceMapPaths := linkedpackage.Search(folder, ".js.map")
+		sourceMapPaths := linkedpackage.SearchFS(folder.fsys, folder.name, ".js.map")
 		for _, sourceMapPath := range sourceMapPaths {
-			smModules, err := linkedpackage.ParseJSSourcemapFile(sourceMapPath)
+			smModules, err := linkedpackage.ParseJSSourcemapFS(folder.fsys, sourceMapPath)
 			if err != nil {
 				log.Println(err)
 				continue
@@ -109,15 +730,64 @@ func readJSPackages(folders []string, extraPackages []string, root string) []lin
 			modules = append(modules, smModules...)
 		}
 
-		sourcePaths := linkedpackage.Search(folder, ".js")
+		sourcePaths := linkedpackage.SearchFS(folder.fsys, folder.name, ".js")
 		for _, sourcePath := range sourcePaths {
-			smModules, err := linkedpackage.ParseJSWebPack(sourcePath)
+			smModules, err := linkedpackage.ParseJSWebPackFS(folder.fsys, sourcePath)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			modules = append(modules, smModules...)
+		}
+
+		// license banners kept by minifiers and .LICENSE.txt files extracted by terser
+		bannerPaths := append(append([]string{}, sourcePaths...), linkedpackage.SearchFS(folder.fsys, folder.name, ".LICENSE.txt")...)
+		for _, bannerPath := range bannerPaths {
+			bannerModules, err := linkedpackage.ParseJSBannerFS(folder.fsys, bannerPath)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			modules = append(modules, bannerModules...)
+		}
+
+		cssSourceMapPaths := linkedpackage.SearchFS(folder.fsys, folder.name, ".css.map")
+		for _, sourceMapPath := range cssSourceMapPaths {
+			smModules, err := linkedpackage.ParseCSSSourcemapFS(folder.fsys, sourceMapPath)
 			if err != nil {
 				log.Println(err)
 				continue
 			}
 			modules = append(modules, smModules...)
 		}
+
+		cssPaths := linkedpackage.SearchFS(folder.fsys, folder.name, ".css")
+		for _, cssPath := range cssPaths {
+			bannerModules, err := linkedpackage.ParseCSSBannerFS(folder.fsys, cssPath)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			modules = append(modules, bannerModules...)
+		}
+
+		assetPaths := linkedpackage.SearchFS(folder.fsys, folder.name, linkedpackage.AssetExtensions()...)
+		if len(assetPaths) > 0 && assetIndex == nil {
+			var err error
+			assetIndex, err = linkedpackage.NewJSAssetIndexFS(root.fsys, root.name)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+		}
+		for _, assetPath := range assetPaths {
+			assetModules, err := assetIndex.FindFS(folder.fsys, assetPath)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			modules = append(modules, assetModules...)
+		}
 	}
 	for _, extra := range extraPackages {
 		modules = append(modules, linkedpackage.Module{
@@ -129,10 +799,19 @@ func readJSPackages(folders []string, extraPackages []string, root string) []lin
 	modules = linkedpackage.UniqueModules(modules)
 	parsedModules := []linkedpackage.Module{}
 	for _, module := range modules {
-		err := linkedpackage.ReadProjectData(&module, root)
-		if err != nil {
+		err := linkedpackage.ReadProjectDataFS(&module, root.fsys, root.name)
+		if err != nil && module.LicenseContent != "" {
+			// the package is not installed, but the bundle has its license banner
+			fmt.Fprintf(os.Stderr, "%s: license banner in the bundle is used: %s\n", module.Name, err.Error())
+		} else if err != nil {
 			log.Println(err)
 			continue
+		} else if dirHash {
+			hash, err := linkedpackage.HashDirFS(root.fsys, root.join(module.Path).name)
+			if err != nil {
+				log.Println(err)
+			}
+			module.DirHash = hash
 		}
 		parsedModules = append(parsedModules, module)
 	}
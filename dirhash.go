@@ -0,0 +1,77 @@
+package linkedpackage
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DirHashPrefix is the prefix of HashDir's result. The format is same as Go's module "h1:" hash.
+const DirHashPrefix = "h1:"
+
+// HashDir calculates deterministic hash of the files in the directory.
+//
+// It is SHA-256 of the sorted list of "<sha256 of file>  <slash separated relative path>\n" lines.
+// Nested node_modules folders are skipped because they are other packages.
+func HashDir(dir string) (string, error) {
+	return HashDirFS(DirFS(dir), ".")
+}
+
+// HashDirFS is HashDir for the folder in the file system.
+func HashDirFS(fsys fs.FS, dir string) (string, error) {
+	var files []string
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name != dir && d.Name() == "node_modules" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel := name
+		if dir != "." {
+			rel = strings.TrimPrefix(name, dir+"/")
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, file := range files {
+		if strings.Contains(file, "\n") {
+			return "", fmt.Errorf("dirhash: filename with newline: %q", file)
+		}
+		fh, err := hashFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%x  %s\n", fh, file)
+	}
+	return DirHashPrefix + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(fsys fs.FS, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,37 @@
+package linkedpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "nested node_modules is skipped",
+			dir:  "testdata/lockfile/v2/node_modules/foo",
+			want: "h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60=",
+		},
+		{
+			name:    "missing folder",
+			dir:     "testdata/lockfile/v2/node_modules/missing",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HashDir(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HashDir() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,570 @@
+package linkedpackage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File systems that the scanners accept:
+//
+//   - DirFS: OS folder (asar archives in it are read as folders)
+//   - OpenAsar: Electron's asar archive
+//   - OpenImage: container image
+//   - NewTarFS: tar archive
+//   - NewZipFS: zip archive (jar, war, wheel and nupkg files)
+//   - NewMemFS: in-memory files
+
+// fsPath joins the elements into the path for fs.FS. Module.Path's leading "/" is removed.
+func fsPath(elem ...string) string {
+	return path.Clean(strings.TrimPrefix(path.Join(elem...), "/"))
+}
+
+// packageFilePath validates the file name written in the package's metadata (e.g. "SEE LICENSE IN <file>")
+// and returns it as the clean slash path relative to the package folder. Absolute paths and paths out of
+// the package folder are rejected.
+func packageFilePath(name string) (string, bool) {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if path.IsAbs(name) || (len(name) >= 2 && name[1] == ':') || name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
+
+// DirFS returns the file system of the OS folder. Like Electron's fs module, asar archives in the folder
+// are read as folders, and "<archive>.unpacked" folders are hidden because their files are listed in the archives.
+func DirFS(dir string) fs.FS {
+	if dir == "" {
+		dir = "."
+	}
+	return hostFS(dir)
+}
+
+type hostFS string
+
+func (h hostFS) join(name string) string {
+	return filepath.Join(string(h), filepath.FromSlash(name))
+}
+
+func (h hostFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	// folders list asar archives as folders
+	if info, err := h.Stat(name); err == nil && info.IsDir() {
+		entries, err := h.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &virtualDir{info: info, entries: entries}, nil
+	}
+	if a, inner, ok := splitAsarPath(h.join(name)); ok {
+		return a.Open(inner)
+	}
+	return os.Open(h.join(name))
+}
+
+func (h hostFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if a, inner, ok := splitAsarPath(h.join(name)); ok {
+		info, err := a.Stat(inner)
+		if err == nil && inner == "." {
+			info = renamedFileInfo{FileInfo: info, name: filepath.Base(a.path)}
+		}
+		return info, err
+	}
+	return os.Stat(h.join(name))
+}
+
+func (h hostFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	dir := h.join(name)
+	if a, inner, ok := splitAsarPath(dir); ok {
+		return a.ReadDir(inner)
+	}
+	entries, err := os.ReadDir(dir)
+	result := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".asar") {
+			if a, err := openAsarCached(filepath.Join(dir, entry.Name())); err == nil {
+				info, _ := a.Stat(".")
+				result = append(result, fs.FileInfoToDirEntry(renamedFileInfo{FileInfo: info, name: entry.Name()}))
+				continue
+			}
+		} else if entry.IsDir() && strings.HasSuffix(entry.Name(), ".asar.unpacked") {
+			if _, err := os.Stat(filepath.Join(dir, strings.TrimSuffix(entry.Name(), ".unpacked"))); err == nil {
+				continue
+			}
+		}
+		result = append(result, entry)
+	}
+	return result, err
+}
+
+// Lstat returns the information of the file without following the symbolic link.
+func (h hostFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	if a, inner, ok := splitAsarPath(h.join(name)); ok && inner != "." {
+		return a.Lstat(inner)
+	} else if ok {
+		return h.Stat(name)
+	}
+	return os.Lstat(h.join(name))
+}
+
+// ReadLink returns the target of the symbolic link.
+func (h hostFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	if a, inner, ok := splitAsarPath(h.join(name)); ok {
+		return a.ReadLink(inner)
+	}
+	return os.Readlink(h.join(name))
+}
+
+type renamedFileInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (i renamedFileInfo) Name() string { return i.name }
+
+// virtualFS is the read-only file system of the indexed entries. It is the base of asar archives,
+// container images, tar archives and in-memory files. Symbolic links are followed inside the file system.
+type virtualFS struct {
+	root *virtualEntry
+}
+
+type virtualEntry struct {
+	name    string
+	mode    fs.FileMode
+	size    int64
+	modTime time.Time
+	// link is the target of the symbolic link. Absolute targets are from the root of the file system.
+	link     string
+	children map[string]*virtualEntry
+	open     func() (io.ReadCloser, error)
+}
+
+func newVirtualFS() *virtualFS {
+	return &virtualFS{
+		root: &virtualEntry{name: ".", mode: fs.ModeDir | 0755, children: map[string]*virtualEntry{}},
+	}
+}
+
+// add puts the entry at the slash separated path. Missing parent folders are created.
+// It returns false if one of the parents is not a folder.
+func (v *virtualFS) add(name string, entry *virtualEntry) bool {
+	dir := v.root
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := dir.children[part]
+		if !ok {
+			child = &virtualEntry{name: part, mode: fs.ModeDir | 0755, children: map[string]*virtualEntry{}}
+			dir.children[part] = child
+		} else if child.children == nil {
+			return false
+		}
+		dir = child
+	}
+	entry.name = parts[len(parts)-1]
+	if entry.mode.IsDir() {
+		if old, ok := dir.children[entry.name]; ok && old.children != nil {
+			entry.children = old.children
+		} else if entry.children == nil {
+			entry.children = map[string]*virtualEntry{}
+		}
+	}
+	dir.children[entry.name] = entry
+	return true
+}
+
+func (v *virtualFS) lookup(op, name string) (*virtualEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	entry, err := v.resolve(name, 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return entry, nil
+}
+
+func (v *virtualFS) resolve(name string, depth int) (*virtualEntry, error) {
+	if depth > 40 {
+		return nil, errors.New("too many levels of symbolic links")
+	}
+	entry := v.root
+	if name == "." {
+		return entry, nil
+	}
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		child, ok := entry.children[part]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		if child.mode&fs.ModeSymlink != 0 {
+			target := child.link
+			if !path.IsAbs(target) {
+				target = path.Join("/", strings.Join(parts[:i], "/"), target)
+			}
+			var err error
+			child, err = v.resolve(fsPath("/", target), depth+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entry = child
+	}
+	return entry, nil
+}
+
+// lookupLink returns the entry without following it if it is a symbolic link.
+func (v *virtualFS) lookupLink(op, name string) (*virtualEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return v.root, nil
+	}
+	dir, err := v.resolve(path.Dir(name), 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	entry, ok := dir.children[path.Base(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return entry, nil
+}
+
+// Lstat returns the information of the file without following the symbolic link.
+func (v *virtualFS) Lstat(name string) (fs.FileInfo, error) {
+	entry, err := v.lookupLink("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return virtualFileInfo{entry}, nil
+}
+
+// ReadLink returns the target of the symbolic link. Absolute targets are from the root of the file system.
+func (v *virtualFS) ReadLink(name string) (string, error) {
+	entry, err := v.lookupLink("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	if entry.mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return entry.link, nil
+}
+
+// Open opens the file or folder. Symbolic links are followed.
+func (v *virtualFS) Open(name string) (fs.File, error) {
+	entry, err := v.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := virtualFileInfo{entry}
+	if entry.mode.IsDir() {
+		return &virtualDir{info: info, entries: entry.dirEntries()}, nil
+	}
+	if entry.open == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	r, err := entry.open()
+	if err != nil {
+		return nil, err
+	}
+	if ra, ok := r.(io.ReaderAt); ok {
+		// binaries are parsed without reading the whole content
+		return &virtualReaderAtFile{virtualFile: &virtualFile{ReadCloser: r, info: info}, ReaderAt: ra}, nil
+	}
+	return &virtualFile{ReadCloser: r, info: info}, nil
+}
+
+// Stat returns the information of the file or folder. Symbolic links are followed.
+func (v *virtualFS) Stat(name string) (fs.FileInfo, error) {
+	entry, err := v.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return virtualFileInfo{entry}, nil
+}
+
+// ReadDir returns the entries of the folder sorted by name.
+func (v *virtualFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := v.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return entry.dirEntries(), nil
+}
+
+func (e *virtualEntry) dirEntries() []fs.DirEntry {
+	result := make([]fs.DirEntry, 0, len(e.children))
+	for _, child := range e.children {
+		result = append(result, fs.FileInfoToDirEntry(virtualFileInfo{child}))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+type virtualFileInfo struct {
+	entry *virtualEntry
+}
+
+func (i virtualFileInfo) Name() string       { return i.entry.name }
+func (i virtualFileInfo) Size() int64        { return i.entry.size }
+func (i virtualFileInfo) Mode() fs.FileMode  { return i.entry.mode }
+func (i virtualFileInfo) ModTime() time.Time { return i.entry.modTime }
+func (i virtualFileInfo) IsDir() bool        { return i.entry.mode.IsDir() }
+func (i virtualFileInfo) Sys() interface{}   { return nil }
+
+type virtualFile struct {
+	io.ReadCloser
+	info fs.FileInfo
+}
+
+func (f *virtualFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+type virtualReaderAtFile struct {
+	*virtualFile
+	io.ReaderAt
+}
+
+type virtualDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *virtualDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *virtualDir) Close() error               { return nil }
+func (d *virtualDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (d *virtualDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
+
+// NewMemFS returns the in-memory file system. The keys are slash separated paths.
+func NewMemFS(files map[string][]byte) fs.FS {
+	v := newVirtualFS()
+	for name, content := range files {
+		content := content
+		if name, ok := imagePath(name); ok {
+			v.add(name, &virtualEntry{
+				mode: 0644,
+				size: int64(len(content)),
+				open: func() (io.ReadCloser, error) {
+					return ioutil.NopCloser(bytes.NewReader(content)), nil
+				},
+			})
+		}
+	}
+	return v
+}
+
+// NewTarFS returns the file system of the uncompressed tar archive. File contents are read from r when they are opened.
+func NewTarFS(r io.ReaderAt, size int64) (fs.FS, error) {
+	sr := io.NewSectionReader(r, 0, size)
+	tr := tar.NewReader(sr)
+	v := newVirtualFS()
+	files := map[string]*virtualEntry{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return v, nil
+		} else if err != nil {
+			return nil, err
+		}
+		name, ok := imagePath(header.Name)
+		if !ok {
+			continue
+		}
+		// tar.Reader doesn't read ahead, so the current position is the beginning of the content
+		offset, err := sr.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			v.add(name, &virtualEntry{mode: fs.ModeDir | header.FileInfo().Mode().Perm(), modTime: header.ModTime})
+		case tar.TypeReg, tar.TypeRegA:
+			entry := &virtualEntry{
+				mode:    header.FileInfo().Mode().Perm(),
+				size:    header.Size,
+				modTime: header.ModTime,
+				open:    sectionOpener(r, offset, header.Size),
+			}
+			if v.add(name, entry) {
+				files[name] = entry
+			}
+		case tar.TypeSymlink:
+			v.add(name, &virtualEntry{mode: fs.ModeSymlink | 0777, link: header.Linkname, modTime: header.ModTime})
+		case tar.TypeLink:
+			linkName, _ := imagePath(header.Linkname)
+			if target, ok := files[linkName]; ok {
+				link := *target
+				v.add(name, &link)
+			}
+		}
+	}
+}
+
+func sectionOpener(r io.ReaderAt, offset, size int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return sectionReadCloser{SectionReader: io.NewSectionReader(r, offset, size), Closer: ioutil.NopCloser(nil)}, nil
+	}
+}
+
+// sectionReadCloser is the content of the file in the archive. Unlike ioutil.NopCloser, it keeps io.ReaderAt.
+type sectionReadCloser struct {
+	*io.SectionReader
+	io.Closer
+}
+
+// NewZipFS returns the file system of the zip archive. Unlike *zip.Reader, folders that have no entries in the archive
+// are listed, and symbolic links that are stored by Info-ZIP are followed.
+func NewZipFS(r io.ReaderAt, size int64) (fs.FS, error) {
+	v, err := newZipFS(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func newZipFS(r io.ReaderAt, size int64) (*virtualFS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	v := newVirtualFS()
+	for _, f := range zr.File {
+		f := f
+		name, ok := imagePath(f.Name)
+		if !ok {
+			continue
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			v.add(name, &virtualEntry{mode: fs.ModeDir | mode.Perm(), modTime: f.Modified})
+		case mode&fs.ModeSymlink != 0:
+			link, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			v.add(name, &virtualEntry{mode: fs.ModeSymlink | 0777, link: string(link), modTime: f.Modified})
+		default:
+			v.add(name, &virtualEntry{
+				mode:    mode.Perm(),
+				size:    int64(f.UncompressedSize64),
+				modTime: f.Modified,
+				open: func() (io.ReadCloser, error) {
+					return f.Open()
+				},
+			})
+		}
+	}
+	return v, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// ZipFS is the file system of the zip archive that is opened by OpenZipFS.
+type ZipFS struct {
+	*virtualFS
+	closer io.Closer
+}
+
+// Close closes the archive file.
+func (z *ZipFS) Close() error {
+	return z.closer.Close()
+}
+
+// OpenZipFS opens the zip archive in the file system. Archives on the OS are read on demand, so the file is kept open
+// until the returned file system is closed.
+func OpenZipFS(fsys fs.FS, name string) (*ZipFS, error) {
+	r, closer, err := openReaderAt(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	v, err := newZipFS(r, r.Size())
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return &ZipFS{virtualFS: v, closer: closer}, nil
+}
+
+// openReaderAt returns the random access reader of the file and its closer. Files of the OS are read on demand,
+// and others (e.g. files in compressed layers and archives) are read into memory.
+func openReaderAt(fsys fs.FS, name string) (*io.SectionReader, io.Closer, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, nil, &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+	if r, ok := f.(io.ReaderAt); ok {
+		return io.NewSectionReader(r, 0, info.Size()), f, nil
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return io.NewSectionReader(bytes.NewReader(content), 0, int64(len(content))), ioutil.NopCloser(nil), nil
+}
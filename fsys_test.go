@@ -0,0 +1,220 @@
+package linkedpackage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_packageFilePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "EULA.md", want: "EULA.md", wantOk: true},
+		{name: "./docs/../LICENSE", want: "LICENSE", wantOk: true},
+		{name: "docs\\EULA.txt", want: "docs/EULA.txt", wantOk: true},
+		{name: "../../etc/passwd"},
+		{name: "docs/../../LICENSE"},
+		{name: "/etc/passwd"},
+		{name: "\\etc\\passwd"},
+		{name: "C:/Windows/win.ini"},
+		{name: "."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := packageFilePath(tt.name)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestDirFS(t *testing.T) {
+	fsys := DirFS(filepath.Join("testdata", "electron", "resources"))
+	assert.NoError(t, fstest.TestFS(fsys, "app.asar/dist/main.js", "app.asar/node_modules/native-addon/build/Release/addon.node"))
+	// app.asar is a folder and app.asar.unpacked is hidden
+	entries, err := fs.ReadDir(fsys, ".")
+	if assert.NoError(t, err) && assert.Len(t, entries, 1) {
+		assert.Equal(t, "app.asar", entries[0].Name())
+		assert.True(t, entries[0].IsDir())
+	}
+	content, err := fs.ReadFile(fsys, "app.asar/node_modules/native-addon/LICENSE")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT License\n\nCopyright (c) 2022 Native Team\n", string(content))
+
+	_, err = fsys.Open("../electron")
+	assert.Error(t, err)
+}
+
+func TestNewMemFS(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"dist/main.js":                       []byte("/*!****************************************!*\\\n  !*** ./node_modules/left-pad/index.js ***!\n  \\****************************************/\n"),
+		"node_modules/left-pad/package.json": []byte(`{"name": "left-pad", "version": "1.3.0", "license": "WTFPL", "author": "azer"}`),
+		"node_modules/left-pad/LICENSE":      []byte("DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE\n"),
+	})
+	assert.NoError(t, fstest.TestFS(fsys, "dist/main.js", "node_modules/left-pad/package.json"))
+
+	modules, err := ParseJSWebPackFS(fsys, "dist/main.js")
+	assert.NoError(t, err)
+	if assert.Equal(t, []Module{{Lang: "js", Name: "left-pad", Path: "/node_modules/left-pad"}}, modules) {
+		assert.NoError(t, ReadProjectDataFS(&modules[0], fsys, "."))
+		assert.Equal(t, "WTFPL", modules[0].LicenseName)
+		assert.Equal(t, "1.3.0", modules[0].Version)
+		assert.Equal(t, "DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE", modules[0].LicenseContent)
+		assert.Empty(t, modules[0].DirHash)
+	}
+	hash, err := HashDirFS(fsys, "node_modules/left-pad")
+	assert.NoError(t, err)
+	assert.Regexp(t, "^h1:", hash)
+
+	assert.Error(t, ReadProjectDataFS(&Module{Lang: "swift", Name: "Alamofire"}, fsys, "."))
+}
+
+func TestNewTarFS(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "image", "docker-save.tar"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	assert.NoError(t, err)
+	image, err := NewTarFS(f, info.Size())
+	if !assert.NoError(t, err) {
+		return
+	}
+	layer, err := fs.ReadFile(image, "layer1/layer.tar")
+	assert.NoError(t, err)
+	fsys, err := NewTarFS(bytes.NewReader(layer), int64(len(layer)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "regular file",
+			file: "app/keep.txt",
+			want: "keep\n",
+		},
+		{
+			name: "hard link",
+			file: "app/keep-link.txt",
+			want: "keep\n",
+		},
+		{
+			name: "symbolic link",
+			file: "bin/tool",
+			want: "#!/bin/sh\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := fs.ReadFile(fsys, tt.file)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+		})
+	}
+	assert.Equal(t, []string{"app/cache/a.txt", "app/cache/b.txt"}, SearchFS(fsys, "app/cache", ".txt"))
+}
+
+func TestImage_fs(t *testing.T) {
+	img, err := OpenImage(filepath.Join("testdata", "image", "docker-save.tar"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	content, err := fs.ReadFile(img, "app/keep.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "keep v2\n", string(content))
+	// hard link keeps the content of its own layer
+	content, err = fs.ReadFile(img, "app/keep-link.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "keep\n", string(content))
+	content, err = fs.ReadFile(img, "app/tool")
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(content))
+
+	for _, missing := range []string{"app/old.txt", "app/cache/a.txt", "app/escape"} {
+		_, err := fs.Stat(img, missing)
+		assert.Error(t, err, missing)
+	}
+	// files of uncompressed layers are read from the tarball directly
+	f, err := img.Open("app/keep.txt")
+	if assert.NoError(t, err) {
+		_, ok := f.(io.ReaderAt)
+		assert.True(t, ok)
+		f.Close()
+	}
+	assert.NoError(t, img.Close())
+}
+
+func TestImage_fs_compressed(t *testing.T) {
+	img, err := OpenImage(filepath.Join("testdata", "image", "oci.tar"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, fstest.TestFS(img, "app/b.txt", "etc/os-release"))
+	distro, err := ReadOSReleaseFS(img, ".")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, distro)
+	assert.Len(t, img.decompressed, 2)
+
+	var tmpFiles []string
+	for _, f := range img.decompressed {
+		tmpFiles = append(tmpFiles, f.Name())
+	}
+	assert.NoError(t, img.Close())
+	for _, name := range tmpFiles {
+		_, err := os.Stat(name)
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
+
+func TestOpenZipFS(t *testing.T) {
+	jar, err := os.ReadFile(filepath.Join("testdata", "java", "demo.jar"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for name, fsys := range map[string]fs.FS{
+		"os":        DirFS(filepath.Join("testdata", "java")),
+		"in-memory": NewMemFS(map[string][]byte{"demo.jar": jar}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			archive, err := OpenZipFS(fsys, "demo.jar")
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, fstest.TestFS(archive, "BOOT-INF/lib/commons-lang3-3.12.0.jar"))
+			assert.Equal(t, []string{
+				"BOOT-INF/lib/commons-lang3-3.12.0.jar",
+				"BOOT-INF/lib/noinfo-1.0-SNAPSHOT.jar",
+			}, SearchFS(archive, ".", ".jar"))
+
+			modules, err := ParseJavaArchiveFS(fsys, "demo.jar")
+			assert.NoError(t, err)
+			assert.Len(t, modules, 2)
+		})
+	}
+
+	_, err = OpenZipFS(DirFS(filepath.Join("testdata", "java")), ".")
+	assert.Error(t, err)
+
+	// the file of the OS is closed with the archive
+	archive, err := OpenZipFS(DirFS(filepath.Join("testdata", "java")), "demo.jar")
+	if assert.NoError(t, err) {
+		f := archive.closer.(*os.File)
+		assert.NoError(t, archive.Close())
+		_, err = f.Stat()
+		assert.True(t, errors.Is(err, os.ErrClosed))
+	}
+}
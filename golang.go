@@ -0,0 +1,168 @@
+package linkedpackage
+
+import (
+	"debug/buildinfo"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"unicode"
+)
+
+// ParseGoBinary reads modules linked into the Go executable from its embedded build information.
+// Module.Path is the directory in the module cache ("/<escaped module path>@<version>").
+// Modules replaced by local directories have neither Version nor Path because they are not in the module cache.
+func ParseGoBinary(path string) ([]Module, error) {
+	return ParseGoBinaryFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseGoBinaryFS reads modules linked into the Go executable in the file system.
+func ParseGoBinaryFS(fsys fs.FS, name string) ([]Module, error) {
+	r, closer, err := openReaderAt(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	info, err := buildinfo.Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var result []Module
+	for _, dep := range info.Deps {
+		result = append(result, goModuleOf(dep))
+	}
+	return result, nil
+}
+
+// goModuleOf converts the dependency in the build information into Module.
+func goModuleOf(dep *debug.Module) Module {
+	module := Module{
+		Lang:    "go",
+		Name:    dep.Path,
+		Version: dep.Version,
+		DirHash: dep.Sum,
+	}
+	sourcePath := dep.Path
+	if dep.Replace != nil {
+		// the code comes from the replacement
+		sourcePath = dep.Replace.Path
+		module.Version = dep.Replace.Version
+		module.DirHash = dep.Replace.Sum
+	}
+	// a local directory replacement ("../foo") has no version and is not in the module cache
+	if module.Version != "" {
+		module.Path = "/" + escapeGoModulePath(sourcePath) + "@" + module.Version
+	}
+	return module
+}
+
+// escapeGoModulePath converts the module path into the module cache's case-insensitive form ("!" + lower case letter).
+func escapeGoModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// goModCache returns the module cache folder like `go env GOMODCACHE`.
+func goModCache() string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// projectGoReader reads license of the Go module. root is the Go project folder; the module is searched in
+// its vendor folder first, then in the module cache.
+func projectGoReader(module *Module, root string) error {
+	if info, err := os.Stat(filepath.Join(root, "vendor", filepath.FromSlash(module.Name))); root == "" || err != nil || !info.IsDir() {
+		root = goModCache()
+		if root == "" {
+			return errors.New("module cache is not found")
+		}
+	}
+	return projectGoReaderFS(module, DirFS(root), ".")
+}
+
+// projectGoReaderFS reads license of the Go module. root is the Go project folder that has the vendor folder
+// or the module cache folder in the file system.
+func projectGoReaderFS(module *Module, fsys fs.FS, root string) error {
+	vendorPath := "/vendor/" + module.Name
+	if info, err := fs.Stat(fsys, fsPath(root, vendorPath)); err == nil && info.IsDir() {
+		module.Path = vendorPath
+	}
+	if owner, ok := goRepositoryOwner(module.Name); ok {
+		module.Repository = "https://" + strings.Join(strings.SplitN(module.Name, "/", 4)[:3], "/")
+		module.Authors = []Author{owner}
+		module.Author = owner.displayName()
+	} else {
+		module.Author = module.Name + " authors"
+	}
+	if module.Path == "" {
+		// the module replaced by the local directory is only found in the vendor folder
+		fmt.Fprintf(os.Stderr, "%s: local module is not vendored\n", module.Name)
+		module.LicenseName = "no license"
+		return nil
+	}
+	if err := module.readLicenseFS(fsys, root); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		module.LicenseName = "no license"
+		return nil
+	}
+	module.LicenseName = guessLicenseName(module.LicenseContent)
+	return nil
+}
+
+// goRepositoryOwner returns the owner of the repository if the module is hosted on the well-known site.
+func goRepositoryOwner(modulePath string) (Author, bool) {
+	fragments := strings.Split(modulePath, "/")
+	if len(fragments) < 3 {
+		return Author{}, false
+	}
+	for _, host := range jsRepositoryHosts {
+		if strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/") == fragments[0] {
+			return Author{
+				Name: fragments[1],
+				URL:  host + fragments[1],
+				Role: AuthorRoleOwner,
+			}, true
+		}
+	}
+	return Author{}, false
+}
+
+func goPURL(module Module) PURL {
+	result := PURL{
+		Type:    "golang",
+		Name:    module.Name,
+		Version: module.Version,
+	}
+	if i := strings.LastIndex(module.Name, "/"); i != -1 {
+		result.Namespace = module.Name[:i]
+		result.Name = module.Name[i+1:]
+	}
+	return result
+}
+
+func init() {
+	RegisterProjectDataReader("go", projectGoReader)
+	RegisterProjectDataReaderFS("go", projectGoReaderFS)
+	RegisterPURLBuilder("go", goPURL)
+}
@@ -0,0 +1,154 @@
+package linkedpackage
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoBinary(t *testing.T) {
+	// test binary has build information of this module's dependencies
+	modules, err := ParseGoBinary(os.Args[0])
+	if err != nil {
+		t.Skip("test binary doesn't have build information:", err)
+	}
+	var testify *Module
+	for i, m := range modules {
+		if m.Name == "github.com/stretchr/testify" {
+			testify = &modules[i]
+		}
+	}
+	if assert.NotNil(t, testify) {
+		assert.Equal(t, "go", testify.Lang)
+		assert.Equal(t, "v1.7.0", testify.Version)
+		assert.Equal(t, "/github.com/stretchr/testify@v1.7.0", testify.Path)
+		assert.Regexp(t, "^h1:", testify.DirHash)
+	}
+
+	_, err = ParseGoBinary(filepath.Join("testdata", "goproject", "vendor", "modules.txt"))
+	assert.Error(t, err)
+}
+
+func Test_goModuleOf(t *testing.T) {
+	tests := []struct {
+		name string
+		dep  *debug.Module
+		want Module
+	}{
+		{
+			name: "module",
+			dep:  &debug.Module{Path: "github.com/Acme/widget", Version: "v1.2.0", Sum: "h1:abc="},
+			want: Module{Lang: "go", Name: "github.com/Acme/widget", Version: "v1.2.0", Path: "/github.com/!acme/widget@v1.2.0", DirHash: "h1:abc="},
+		},
+		{
+			name: "module replacement",
+			dep: &debug.Module{Path: "github.com/Acme/widget", Version: "v1.2.0", Sum: "h1:abc=",
+				Replace: &debug.Module{Path: "github.com/fork/widget", Version: "v1.2.1", Sum: "h1:def="}},
+			want: Module{Lang: "go", Name: "github.com/Acme/widget", Version: "v1.2.1", Path: "/github.com/fork/widget@v1.2.1", DirHash: "h1:def="},
+		},
+		{
+			name: "local directory replacement",
+			dep: &debug.Module{Path: "github.com/Acme/widget", Version: "v1.2.0", Sum: "h1:abc=",
+				Replace: &debug.Module{Path: "../widget"}},
+			want: Module{Lang: "go", Name: "github.com/Acme/widget"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, goModuleOf(tt.dep))
+		})
+	}
+}
+
+func Test_escapeGoModulePath(t *testing.T) {
+	assert.Equal(t, "github.com/!azure/azure-sdk-for-go", escapeGoModulePath("github.com/Azure/azure-sdk-for-go"))
+	assert.Equal(t, "golang.org/x/text", escapeGoModulePath("golang.org/x/text"))
+}
+
+func Test_projectGoReader(t *testing.T) {
+	modCache, err := filepath.Abs(filepath.Join("testdata", "gomodcache"))
+	assert.NoError(t, err)
+	os.Setenv("GOMODCACHE", modCache)
+	defer os.Unsetenv("GOMODCACHE")
+
+	tests := []struct {
+		name     string
+		module   Module
+		root     string
+		wantPath string
+		wantRepo string
+		wantName string
+		author   string
+	}{
+		{
+			name:     "module cache",
+			module:   Module{Lang: "go", Name: "github.com/Acme/widget", Version: "v1.2.0", Path: "/github.com/!acme/widget@v1.2.0"},
+			root:     filepath.Join("testdata", "goproject"),
+			wantPath: "/github.com/!acme/widget@v1.2.0",
+			wantRepo: "https://github.com/Acme/widget",
+			wantName: "MIT",
+			author:   "Acme",
+		},
+		{
+			name:     "vendor",
+			module:   Module{Lang: "go", Name: "golang.org/x/text", Version: "v0.3.6", Path: "/golang.org/x/text@v0.3.6"},
+			root:     filepath.Join("testdata", "goproject"),
+			wantPath: "/vendor/golang.org/x/text",
+			wantName: "BSD-3-Clause",
+			author:   "golang.org/x/text authors",
+		},
+		{
+			name:     "vendored local module",
+			module:   Module{Lang: "go", Name: "golang.org/x/text"},
+			root:     filepath.Join("testdata", "goproject"),
+			wantPath: "/vendor/golang.org/x/text",
+			wantName: "BSD-3-Clause",
+			author:   "golang.org/x/text authors",
+		},
+		{
+			name:     "local module",
+			module:   Module{Lang: "go", Name: "github.com/Acme/widget"},
+			root:     filepath.Join("testdata", "goproject"),
+			wantRepo: "https://github.com/Acme/widget",
+			wantName: "no license",
+			author:   "Acme",
+		},
+		{
+			name:     "missing",
+			module:   Module{Lang: "go", Name: "example.com/missing", Version: "v1.0.0", Path: "/example.com/missing@v1.0.0"},
+			wantPath: "/example.com/missing@v1.0.0",
+			wantName: "no license",
+			author:   "example.com/missing authors",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, tt.root))
+			assert.Equal(t, tt.wantPath, module.Path)
+			assert.Equal(t, tt.wantRepo, module.Repository)
+			assert.Equal(t, tt.wantName, module.LicenseName)
+			assert.Equal(t, tt.author, module.Author)
+		})
+	}
+}
+
+func Test_projectGoReaderFS(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"pkg/mod/github.com/!acme/widget@v1.2.0/LICENSE": []byte("MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\n"),
+		"pkg/mod/github.com/!acme/widget@v1.2.0/go.mod":  []byte("module github.com/Acme/widget\n"),
+	})
+	module := Module{Lang: "go", Name: "github.com/Acme/widget", Version: "v1.2.0", Path: "/github.com/!acme/widget@v1.2.0"}
+	assert.NoError(t, ReadProjectDataFS(&module, fsys, "pkg/mod"))
+	assert.Equal(t, "/github.com/!acme/widget@v1.2.0", module.Path)
+	assert.Equal(t, "MIT", module.LicenseName)
+	assert.Equal(t, "Acme", module.Author)
+}
+
+func Test_goPURL(t *testing.T) {
+	m := Module{Lang: "go", Name: "github.com/Acme/widget", Version: "v1.2.0"}
+	assert.Equal(t, "pkg:golang/github.com/Acme/widget@v1.2.0", m.PURL())
+}
@@ -0,0 +1,590 @@
+package linkedpackage
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Image is the file system of the container image reconstructed from the layers of
+// `docker save` or OCI image layout tarball. Layer whiteouts are applied to the index of the entries
+// when the image is opened, and file contents are not read until they are opened or Extract is called.
+// Image implements fs.FS. Files of uncompressed layers are read from the tarball directly. Compressed layers
+// are decompressed into temporary files when their files are opened first, and Close removes them.
+type Image struct {
+	*virtualFS
+	path   string
+	layers []imageLayer
+	// entries are the visible entries of the merged file system. The key is slash separated path without leading "/".
+	entries map[string]imageEntry
+	// children are the names of the child entries of the folders ("." is the root). They may have removed entries.
+	children map[string]map[string]bool
+
+	mu           sync.Mutex
+	decompressed map[int]*os.File
+}
+
+type imageLayer struct {
+	name string
+	// offset and size are the position of the layer in the tarball
+	offset int64
+	size   int64
+}
+
+type imageEntry struct {
+	layer int
+	// index is the position in the layer tar to distinguish the same path that appears twice in one layer
+	index  int
+	header *tar.Header
+	// offset and size are the position of the content in the uncompressed layer tar.
+	// Hard links have the position of the file that they refer in the same layer.
+	offset int64
+	size   int64
+}
+
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// ociManifest is the image index or the image manifest of OCI image layout.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// OpenImage reads the image tarball created by `docker save` or OCI image layout.
+// If the tarball has multiple images, the first one is used.
+func OpenImage(imageTar string) (*Image, error) {
+	f, err := os.Open(imageTar)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	names, err := imageLayers(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", imageTar, err)
+	}
+	layers, err := locateLayers(f, names)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", imageTar, err)
+	}
+	img := &Image{
+		path:         imageTar,
+		layers:       layers,
+		entries:      map[string]imageEntry{},
+		children:     map[string]map[string]bool{},
+		decompressed: map[int]*os.File{},
+	}
+	for i := range layers {
+		// regular files of the layer that hard links refer
+		files := map[string]imageEntry{}
+		err := img.walkLayer(f, i, func(index int, name string, header *tar.Header, offset int64, r io.Reader) error {
+			entry := imageEntry{layer: i, index: index, header: header, offset: offset, size: header.Size}
+			switch header.Typeflag {
+			case tar.TypeReg, tar.TypeRegA:
+				files[name] = entry
+			case tar.TypeLink:
+				linkName, _ := imagePath(header.Linkname)
+				target, ok := files[linkName]
+				if !ok {
+					return nil
+				}
+				entry.offset, entry.size = target.offset, target.size
+			}
+			img.apply(name, entry)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", imageTar, err)
+		}
+	}
+	img.buildFS()
+	return img, nil
+}
+
+// Close removes the temporary files of the decompressed layers.
+func (img *Image) Close() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	var result error
+	for i, f := range img.decompressed {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil && result == nil {
+			result = err
+		}
+		delete(img.decompressed, i)
+	}
+	return result
+}
+
+// buildFS creates the index of fs.FS from the visible entries.
+func (img *Image) buildFS() {
+	img.virtualFS = newVirtualFS()
+	for _, name := range img.Files() {
+		if !img.visible(name) {
+			continue
+		}
+		entry := img.entries[name]
+		header := entry.header
+		switch header.Typeflag {
+		case tar.TypeDir:
+			img.add(name, &virtualEntry{mode: fs.ModeDir | header.FileInfo().Mode().Perm(), modTime: header.ModTime})
+		case tar.TypeReg, tar.TypeRegA, tar.TypeLink:
+			// the content of the hard link is the file that it refers in the same layer
+			img.add(name, &virtualEntry{
+				mode:    header.FileInfo().Mode().Perm(),
+				size:    entry.size,
+				modTime: header.ModTime,
+				open:    img.fileOpener(entry),
+			})
+		case tar.TypeSymlink:
+			img.add(name, &virtualEntry{mode: fs.ModeSymlink | 0777, link: header.Linkname, modTime: header.ModTime})
+		}
+	}
+}
+
+// fileOpener returns the function that reads the content of the entry.
+func (img *Image) fileOpener(entry imageEntry) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		img.mu.Lock()
+		layer, ok := img.decompressed[entry.layer]
+		img.mu.Unlock()
+		if ok {
+			return sectionReadCloser{SectionReader: io.NewSectionReader(layer, entry.offset, entry.size), Closer: ioutil.NopCloser(nil)}, nil
+		}
+		f, err := os.Open(img.path)
+		if err != nil {
+			return nil, err
+		}
+		l := img.layers[entry.layer]
+		compressed, err := isCompressedLayer(io.NewSectionReader(f, l.offset, l.size))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", l.name, err)
+		}
+		if !compressed {
+			return sectionReadCloser{SectionReader: io.NewSectionReader(f, l.offset+entry.offset, entry.size), Closer: f}, nil
+		}
+		defer f.Close()
+		layer, err = img.decompress(f, entry.layer)
+		if err != nil {
+			return nil, err
+		}
+		return sectionReadCloser{SectionReader: io.NewSectionReader(layer, entry.offset, entry.size), Closer: ioutil.NopCloser(nil)}, nil
+	}
+}
+
+// decompress writes the uncompressed tar of the layer into the temporary file. It is done once for each layer.
+func (img *Image) decompress(f *os.File, i int) (*os.File, error) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	if layer, ok := img.decompressed[i]; ok {
+		return layer, nil
+	}
+	r, err := img.openLayer(f, i)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	tmp, err := ioutil.TempFile("", "linkedpackage-layer")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("%s: %w", img.layers[i].name, err)
+	}
+	img.decompressed[i] = tmp
+	return tmp, nil
+}
+
+// imageLayers returns the layer file names in the tarball from the bottom to the top.
+func imageLayers(f *os.File) ([]string, error) {
+	content, err := readTarEntry(f, "manifest.json")
+	if err == nil {
+		var manifests []dockerManifest
+		if err := json.Unmarshal(content, &manifests); err != nil {
+			return nil, fmt.Errorf("manifest.json: %w", err)
+		}
+		if len(manifests) == 0 {
+			return nil, errors.New("manifest.json doesn't have images")
+		}
+		return manifests[0].Layers, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	content, err = readTarEntry(f, "index.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("neither manifest.json nor index.json is found")
+	} else if err != nil {
+		return nil, err
+	}
+	// index.json may refer nested image index (e.g. multi-platform image). Follow the first manifest.
+	for depth := 0; depth < 8; depth++ {
+		var manifest ociManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, err
+		}
+		if len(manifest.Layers) > 0 {
+			var result []string
+			for _, layer := range manifest.Layers {
+				result = append(result, ociBlobPath(layer.Digest))
+			}
+			return result, nil
+		}
+		if len(manifest.Manifests) == 0 {
+			return nil, errors.New("image manifest is not found in index.json")
+		}
+		content, err = readTarEntry(f, ociBlobPath(manifest.Manifests[0].Digest))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, errors.New("image index is nested too deeply")
+}
+
+func ociBlobPath(digest string) string {
+	algorithm, hash, _ := strings.Cut(digest, ":")
+	return "blobs/" + algorithm + "/" + hash
+}
+
+// locateLayers returns the positions of the layers in the tarball.
+func locateLayers(f *os.File, names []string) ([]imageLayer, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	positions := map[string]imageLayer{}
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		// tar.Reader doesn't read ahead, so the current position is the beginning of the content
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		name, _ := imagePath(header.Name)
+		positions[name] = imageLayer{name: name, offset: offset, size: header.Size}
+	}
+	var result []imageLayer
+	for _, name := range names {
+		name, _ = imagePath(name)
+		layer, ok := positions[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		result = append(result, layer)
+	}
+	return result, nil
+}
+
+// readTarEntry reads the file in the (uncompressed) tar. It returns os.ErrNotExist if the file is not found.
+func readTarEntry(f *os.File, name string) ([]byte, error) {
+	r, err := openTarEntry(f, name)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func openTarEntry(f *os.File, name string) (io.Reader, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	name, _ = imagePath(name)
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		} else if err != nil {
+			return nil, err
+		}
+		if entryName, _ := imagePath(header.Name); entryName == name {
+			return tr, nil
+		}
+	}
+}
+
+// imagePath returns slash separated path without leading "/". ".." can't go above the root.
+func imagePath(name string) (string, bool) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	return name, name != ""
+}
+
+// isCompressedLayer returns true if the layer is gzip compressed. zstd compressed layers are not supported.
+func isCompressedLayer(r io.Reader) (bool, error) {
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(r, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+	magic = magic[:n]
+	if len(magic) == 4 && magic[0] == 0x28 && magic[1] == 0xb5 && magic[2] == 0x2f && magic[3] == 0xfd {
+		return false, errors.New("zstd compressed layer is not supported")
+	}
+	return len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b, nil
+}
+
+// openLayer returns the uncompressed tar of the i-th layer. Layers can be uncompressed or gzip compressed.
+func (img *Image) openLayer(f *os.File, i int) (io.ReadCloser, error) {
+	l := img.layers[i]
+	compressed, err := isCompressedLayer(io.NewSectionReader(f, l.offset, l.size))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", l.name, err)
+	}
+	r := io.NewSectionReader(f, l.offset, l.size)
+	if !compressed {
+		return ioutil.NopCloser(r), nil
+	}
+	gr, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", l.name, err)
+	}
+	return gr, nil
+}
+
+// countingReader counts the bytes that are read.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// walkLayer calls fn for each entry of the i-th layer with the position of the content in the uncompressed layer tar.
+func (img *Image) walkLayer(f *os.File, i int, fn func(index int, name string, header *tar.Header, offset int64, r io.Reader) error) error {
+	layer, err := img.openLayer(f, i)
+	if err != nil {
+		return err
+	}
+	defer layer.Close()
+	counter := &countingReader{r: layer}
+	tr := tar.NewReader(counter)
+	for index := 0; ; index++ {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", img.layers[i].name, err)
+		}
+		name, ok := imagePath(header.Name)
+		if !ok {
+			continue
+		}
+		// tar.Reader doesn't read ahead, so the read bytes are the beginning of the content
+		if err := fn(index, name, header, counter.n, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// apply adds the entry of the layer to the merged file system.
+func (img *Image) apply(name string, entry imageEntry) {
+	dir, base := path.Split(name)
+	switch {
+	case base == whiteoutOpaque:
+		// hide all the children of the folder in the lower layers
+		img.remove(strings.TrimSuffix(dir, "/"), entry.layer, false)
+	case strings.HasPrefix(base, whiteoutPrefix):
+		img.remove(dir+strings.TrimPrefix(base, whiteoutPrefix), entry.layer, true)
+	default:
+		if old, ok := img.entries[name]; ok && old.header.Typeflag == tar.TypeDir && entry.header.Typeflag != tar.TypeDir {
+			// a file replaces the folder of the lower layer
+			img.remove(name, entry.layer, false)
+		}
+		img.entries[name] = entry
+		for child := name; child != "."; child = path.Dir(child) {
+			parent := path.Dir(child)
+			if img.children[parent] == nil {
+				img.children[parent] = map[string]bool{}
+			} else if img.children[parent][path.Base(child)] {
+				break
+			}
+			img.children[parent][path.Base(child)] = true
+		}
+	}
+}
+
+// remove deletes the entry (if self is true) and its descendants that come from the lower layers.
+// Only the subtree of the entry is visited.
+func (img *Image) remove(name string, layer int, self bool) {
+	if name == "" {
+		name = "."
+	}
+	if entry, ok := img.entries[name]; self && ok && entry.layer < layer {
+		delete(img.entries, name)
+	}
+	for child := range img.children[name] {
+		childName := child
+		if name != "." {
+			childName = name + "/" + child
+		}
+		img.remove(childName, layer, true)
+		if _, ok := img.entries[childName]; !ok && len(img.children[childName]) == 0 {
+			delete(img.children[name], child)
+			delete(img.children, childName)
+		}
+	}
+}
+
+// Files returns slash separated paths (without leading "/") of the entries in the merged file system.
+func (img *Image) Files() []string {
+	result := make([]string, 0, len(img.entries))
+	for name := range img.entries {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// visible returns false if the parent of the entry is not a folder (e.g. a symbolic link) in the merged file system.
+// Such entries are not extracted not to write files through the symbolic links.
+func (img *Image) visible(name string) bool {
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if entry, ok := img.entries[dir]; ok && entry.header.Typeflag != tar.TypeDir {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract writes folders, regular files and symbolic links of the merged file system into dir.
+// Absolute symbolic links are converted into relative ones so that they point inside dir,
+// and links that go above the root are skipped. Hard links are written as copies.
+// Device files and FIFOs are skipped.
+func (img *Image) Extract(dir string) error {
+	f, err := os.Open(img.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var symlinks []string
+	for i := range img.layers {
+		// hard links are extracted as copies of the files that they refer in the same layer
+		hardLinks := map[string][]string{}
+		for name, entry := range img.entries {
+			if entry.layer == i && entry.header.Typeflag == tar.TypeLink && img.visible(name) {
+				if linkName, ok := imagePath(entry.header.Linkname); ok {
+					hardLinks[linkName] = append(hardLinks[linkName], name)
+				}
+			}
+		}
+		err := img.walkLayer(f, i, func(index int, name string, header *tar.Header, offset int64, r io.Reader) error {
+			entry, ok := img.entries[name]
+			visible := ok && entry.layer == i && entry.index == index && img.visible(name)
+			switch header.Typeflag {
+			case tar.TypeDir:
+				if visible {
+					return os.MkdirAll(filepath.Join(dir, filepath.FromSlash(name)), 0755)
+				}
+			case tar.TypeReg, tar.TypeRegA:
+				var targets []string
+				if visible {
+					targets = append(targets, name)
+				}
+				targets = append(targets, hardLinks[name]...)
+				if len(targets) == 0 {
+					return nil
+				}
+				mode := header.FileInfo().Mode().Perm() | 0600
+				first := filepath.Join(dir, filepath.FromSlash(targets[0]))
+				if err := extractImageFile(first, mode, r); err != nil {
+					return err
+				}
+				for _, target := range targets[1:] {
+					src, err := os.Open(first)
+					if err != nil {
+						return err
+					}
+					err = extractImageFile(filepath.Join(dir, filepath.FromSlash(target)), mode, src)
+					src.Close()
+					if err != nil {
+						return err
+					}
+				}
+			case tar.TypeSymlink:
+				if visible {
+					symlinks = append(symlinks, name)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", img.path, err)
+		}
+	}
+	// symbolic links are created after all files not to write files through them
+	sort.Strings(symlinks)
+	for _, name := range symlinks {
+		header := img.entries[name].header
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		linkTarget := header.Linkname
+		if !path.IsAbs(linkTarget) {
+			linkTarget = path.Join(path.Dir(name), linkTarget)
+			if linkTarget == ".." || strings.HasPrefix(linkTarget, "../") {
+				continue
+			}
+		}
+		rel, err := filepath.Rel(filepath.Join(dir, filepath.FromSlash(path.Dir(name))), filepath.Join(dir, filepath.FromSlash(linkTarget)))
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(rel, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractImageFile(target string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
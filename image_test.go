@@ -0,0 +1,74 @@
+package linkedpackage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  []string
+	}{
+		{
+			name:  "docker save",
+			image: "docker-save.tar",
+			// app/old.txt is deleted by whiteout, and app/cache is replaced by opaque whiteout
+			want: []string{
+				"app", "app/cache", "app/cache/c.txt", "app/escape", "app/keep-link.txt", "app/keep.txt", "app/tool",
+				"bin", "bin/through-link.txt", "usr/bin", "usr/bin/tool",
+			},
+		},
+		{
+			name:  "OCI image layout with nested index",
+			image: "oci.tar",
+			want:  []string{"app/b.txt", "etc/os-release"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := OpenImage(filepath.Join("testdata", "image", tt.image))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, img.Files())
+			}
+		})
+	}
+
+	_, err := OpenImage(filepath.Join("testdata", "rust", "Cargo.lock"))
+	assert.Error(t, err)
+}
+
+func TestImage_Extract(t *testing.T) {
+	img, err := OpenImage(filepath.Join("testdata", "image", "docker-save.tar"))
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "linkedpackage-image")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, img.Extract(dir))
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app", "keep.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "keep v2\n", string(content))
+	// hard link keeps the content of its own layer
+	content, err = ioutil.ReadFile(filepath.Join(dir, "app", "keep-link.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "keep\n", string(content))
+	// absolute symbolic link points inside the extracted folder
+	link, err := os.Readlink(filepath.Join(dir, "app", "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("..", "usr", "bin", "tool"), link)
+	info, err := os.Stat(filepath.Join(dir, "usr", "bin", "tool"))
+	if assert.NoError(t, err) {
+		assert.NotZero(t, info.Mode().Perm()&0111)
+	}
+
+	for _, skipped := range []string{"app/old.txt", "app/cache/a.txt", "app/escape", "usr/bin/through-link.txt"} {
+		_, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(skipped)))
+		assert.True(t, os.IsNotExist(err), skipped)
+	}
+}
@@ -0,0 +1,356 @@
+package linkedpackage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// javaLibFolders are the folders of fat jars (Spring Boot's jar and war) that contain dependency jars.
+var javaLibFolders = []string{"BOOT-INF/lib", "WEB-INF/lib", "WEB-INF/lib-provided"}
+
+// ParseJavaArchive reads libraries bundled into the jar or war file.
+//
+// Dependency jars in BOOT-INF/lib and WEB-INF/lib become modules whose Path is the jar's path in the archive.
+// For shaded (uber) jars that don't have dependency jars, each META-INF/maven/<groupId>/<artifactId> folder becomes
+// a module. Module.Name is "<groupId>:<artifactId>".
+func ParseJavaArchive(archivePath string) ([]Module, error) {
+	return ParseJavaArchiveFS(DirFS(filepath.Dir(archivePath)), filepath.Base(archivePath))
+}
+
+// ParseJavaArchiveFS reads libraries bundled into the jar or war file in the file system. name can also be the folder
+// of the archive that is opened by OpenZipFS or extracted.
+func ParseJavaArchiveFS(fsys fs.FS, name string) ([]Module, error) {
+	archive, closer, err := openJavaArchive(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	var result []Module
+	for _, folder := range javaLibFolders {
+		entries, err := fs.ReadDir(archive, folder)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".jar") {
+				continue
+			}
+			libPath := path.Join(folder, entry.Name())
+			data, err := fs.ReadFile(archive, libPath)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", libPath, err)
+			}
+			module := Module{
+				Lang: "java",
+				Path: "/" + libPath,
+			}
+			if nested, err := NewZipFS(bytes.NewReader(data), int64(len(data))); err == nil {
+				if mavenModules := javaMavenModules(nested); len(mavenModules) > 0 {
+					module.Name = mavenModules[0].Name
+					module.Version = mavenModules[0].Version
+				}
+			}
+			if module.Name == "" {
+				module.Name, module.Version = parseJarFileName(entry.Name())
+			}
+			sha1Sum := sha1.Sum(data)
+			sha256Sum := sha256.Sum256(data)
+			module.Integrity = "sha1-" + base64.StdEncoding.EncodeToString(sha1Sum[:]) + " sha256-" + base64.StdEncoding.EncodeToString(sha256Sum[:])
+			result = append(result, module)
+		}
+	}
+	if len(result) == 0 {
+		// shaded jar. classes of dependencies are merged into the archive
+		result = javaMavenModules(archive)
+	}
+	return result, nil
+}
+
+// openJavaArchive returns the file system of the jar or war file. If name is a folder, it is used as the archive.
+func openJavaArchive(fsys fs.FS, name string) (fs.FS, io.Closer, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		archive, err := fs.Sub(fsys, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return archive, ioutil.NopCloser(nil), nil
+	}
+	archive, err := OpenZipFS(fsys, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, archive, nil
+}
+
+// javaMavenModules returns modules from META-INF/maven/<groupId>/<artifactId>/pom.properties in the archive.
+func javaMavenModules(archive fs.FS) []Module {
+	var result []Module
+	matches, _ := fs.Glob(archive, "META-INF/maven/*/*/pom.properties")
+	for _, match := range matches {
+		data, err := fs.ReadFile(archive, match)
+		if err != nil {
+			continue
+		}
+		properties := parseJavaProperties(data)
+		if properties["artifactId"] == "" {
+			continue
+		}
+		result = append(result, Module{
+			Lang:    "java",
+			Name:    properties["groupId"] + ":" + properties["artifactId"],
+			Version: properties["version"],
+			Path:    "/" + path.Dir(match),
+		})
+	}
+	return result
+}
+
+// parseJavaProperties parses "key=value" lines of .properties file. Escapes and line continuations are not supported.
+func parseJavaProperties(data []byte) map[string]string {
+	result := map[string]string{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i == -1 {
+			continue
+		}
+		result[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return result
+}
+
+var jarVersionPattern = regexp.MustCompile(`^(.+?)-(\d[^-]*(?:-.*)?)\.jar$`)
+
+// parseJarFileName splits "artifact-1.2.3.jar" into artifact name and version.
+func parseJarFileName(name string) (string, string) {
+	if m := jarVersionPattern.FindStringSubmatch(name); m != nil {
+		return m[1], m[2]
+	}
+	return strings.TrimSuffix(name, ".jar"), ""
+}
+
+type javaPOM struct {
+	GroupID     string `xml:"groupId"`
+	ArtifactID  string `xml:"artifactId"`
+	Version     string `xml:"version"`
+	Description string `xml:"description"`
+	URL         string `xml:"url"`
+	Licenses    []struct {
+		Name string `xml:"name"`
+		URL  string `xml:"url"`
+	} `xml:"licenses>license"`
+	Developers []struct {
+		Name  string `xml:"name"`
+		Email string `xml:"email"`
+		URL   string `xml:"url"`
+	} `xml:"developers>developer"`
+	Organization struct {
+		Name string `xml:"name"`
+		URL  string `xml:"url"`
+	} `xml:"organization"`
+	SCM struct {
+		URL string `xml:"url"`
+	} `xml:"scm"`
+	IssueManagement struct {
+		URL string `xml:"url"`
+	} `xml:"issueManagement"`
+}
+
+// projectJavaReader reads pom.xml and license files of the module. root is the jar or war file that contains the module.
+func projectJavaReader(module *Module, root string) error {
+	return projectJavaReaderFS(module, DirFS(filepath.Dir(root)), filepath.Base(root))
+}
+
+// projectJavaReaderFS reads pom.xml and license files of the module. root is the jar or war file in the file system.
+// To read many modules of the same archive, open it once by OpenZipFS and pass the archive as fsys and "." as root.
+func projectJavaReaderFS(module *Module, fsys fs.FS, root string) error {
+	archive, closer, err := openJavaArchive(fsys, root)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	mavenDir := strings.TrimPrefix(module.Path, "/")
+	if strings.HasSuffix(module.Path, ".jar") {
+		data, err := fs.ReadFile(archive, mavenDir)
+		if err != nil {
+			return fmt.Errorf("%s is not found in %s: %w", mavenDir, root, err)
+		}
+		archive, err = NewZipFS(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", mavenDir, err)
+		}
+		mavenDir = ""
+		for _, m := range javaMavenModules(archive) {
+			if m.Name == module.Name {
+				mavenDir = strings.TrimPrefix(m.Path, "/")
+			}
+		}
+	}
+
+	var pom javaPOM
+	if mavenDir != "" {
+		data, err := fs.ReadFile(archive, mavenDir+"/pom.xml")
+		if err == nil {
+			err = xml.Unmarshal(data, &pom)
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
+	}
+
+	var licenses []string
+	for _, l := range pom.Licenses {
+		if name := javaLicenseName(l.Name, l.URL); name != "" {
+			licenses = append(licenses, name)
+		}
+	}
+	if len(licenses) > 0 {
+		module.LicenseName = strings.Join(licenses, ", ")
+	}
+	if strings.HasSuffix(module.Path, ".jar") {
+		// license files of shaded jars are not read because they may belong to other libraries
+		module.LicenseContent = readJavaLicenseFile(archive)
+	}
+	if module.LicenseContent == "" {
+		fmt.Fprintf(os.Stderr, "%s: license file missing\n", module.Name)
+	}
+	switch {
+	case module.LicenseName != "":
+	case module.LicenseContent != "":
+		module.LicenseName = guessLicenseName(module.LicenseContent)
+	default:
+		module.LicenseName = "no license"
+	}
+
+	for _, d := range pom.Developers {
+		author := Author{
+			Name:  strings.TrimSpace(d.Name),
+			Email: strings.TrimSpace(d.Email),
+			URL:   strings.TrimSpace(d.URL),
+			Role:  AuthorRoleAuthor,
+		}
+		if author.Name != "" || author.Email != "" {
+			module.Authors = append(module.Authors, author)
+		}
+	}
+	if name := strings.TrimSpace(pom.Organization.Name); name != "" {
+		module.Authors = append(module.Authors, Author{
+			Name: name,
+			URL:  strings.TrimSpace(pom.Organization.URL),
+			Role: AuthorRoleOwner,
+		})
+	}
+	if len(module.Authors) > 0 {
+		module.Author = module.Authors[0].displayName()
+	} else {
+		module.Author = module.Name + " authors"
+	}
+	module.Description = strings.Join(strings.Fields(pom.Description), " ")
+	module.Homepage = strings.TrimSpace(pom.URL)
+	module.Repository = strings.TrimSpace(pom.SCM.URL)
+	module.Bugs = strings.TrimSpace(pom.IssueManagement.URL)
+	return nil
+}
+
+// readJavaLicenseFile returns the license file at the root or in META-INF of the jar.
+func readJavaLicenseFile(archive fs.FS) string {
+	for _, dir := range []string{".", "META-INF"} {
+		entries, _ := fs.ReadDir(archive, dir)
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !isLicenseFileName(entry.Name()) {
+				continue
+			}
+			if data, err := fs.ReadFile(archive, path.Join(dir, entry.Name())); err == nil {
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
+
+// javaLicenses maps phrases in pom's license names and URLs to SPDX identifiers. Specific ones come first.
+var javaLicenses = []struct {
+	phrases []string
+	id      string
+}{
+	{[]string{"apache", "2.0"}, "Apache-2.0"},
+	{[]string{"apache.org/licenses/license-2.0"}, "Apache-2.0"},
+	{[]string{"eclipse public license", "2.0"}, "EPL-2.0"},
+	{[]string{"eclipse public license", "1.0"}, "EPL-1.0"},
+	{[]string{"eclipse distribution license"}, "BSD-3-Clause"},
+	{[]string{"lesser general public license", "2.1"}, "LGPL-2.1"},
+	{[]string{"lesser general public license", "3"}, "LGPL-3.0"},
+	{[]string{"common development and distribution license", "1.1"}, "CDDL-1.1"},
+	{[]string{"common development and distribution license"}, "CDDL-1.0"},
+	{[]string{"mozilla public license", "2.0"}, "MPL-2.0"},
+	{[]string{"bsd", "3"}, "BSD-3-Clause"},
+	{[]string{"bsd", "2"}, "BSD-2-Clause"},
+	{[]string{"mit license"}, "MIT"},
+	{[]string{"opensource.org/licenses/mit"}, "MIT"},
+}
+
+// javaLicenseName converts pom's free text license name into SPDX identifier if possible.
+func javaLicenseName(name, url string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if strings.EqualFold(name, "MIT") {
+		return "MIT"
+	}
+	src := strings.ToLower(name + " " + url)
+	for _, l := range javaLicenses {
+		matched := true
+		for _, phrase := range l.phrases {
+			if !strings.Contains(src, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return l.id
+		}
+	}
+	if name == "" {
+		return url
+	}
+	return name
+}
+
+func javaPURL(module Module) PURL {
+	result := PURL{
+		Type:    "maven",
+		Name:    module.Name,
+		Version: module.Version,
+	}
+	if group, artifact, ok := strings.Cut(module.Name, ":"); ok {
+		result.Namespace = group
+		result.Name = artifact
+	}
+	return result
+}
+
+func init() {
+	RegisterProjectDataReader("java", projectJavaReader)
+	RegisterProjectDataReaderFS("java", projectJavaReaderFS)
+	RegisterPURLBuilder("java", javaPURL)
+}
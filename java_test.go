@@ -0,0 +1,168 @@
+package linkedpackage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJavaArchive(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		want    []Module
+	}{
+		{
+			name:    "spring boot jar",
+			archive: "demo.jar",
+			want: []Module{
+				{Lang: "java", Name: "org.apache.commons:commons-lang3", Version: "3.12.0", Path: "/BOOT-INF/lib/commons-lang3-3.12.0.jar"},
+				{Lang: "java", Name: "noinfo", Version: "1.0-SNAPSHOT", Path: "/BOOT-INF/lib/noinfo-1.0-SNAPSHOT.jar"},
+			},
+		},
+		{
+			name:    "shaded jar",
+			archive: "shaded.jar",
+			want: []Module{
+				{Lang: "java", Name: "com.google.code.gson:gson", Version: "2.8.9", Path: "/META-INF/maven/com.google.code.gson/gson"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJavaArchive(filepath.Join("testdata", "java", tt.archive))
+			assert.NoError(t, err)
+			for i := range got {
+				if got[i].Integrity != "" {
+					assert.Len(t, (Module{Integrity: got[i].Integrity}).Checksums(), 2)
+					got[i].Integrity = ""
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_projectJavaReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		module  Module
+		want    Module
+	}{
+		{
+			name:    "pom.xml in nested jar",
+			archive: "demo.jar",
+			module:  Module{Lang: "java", Name: "org.apache.commons:commons-lang3", Version: "3.12.0", Path: "/BOOT-INF/lib/commons-lang3-3.12.0.jar"},
+			want: Module{
+				Lang:    "java",
+				Name:    "org.apache.commons:commons-lang3",
+				Version: "3.12.0",
+				Path:    "/BOOT-INF/lib/commons-lang3-3.12.0.jar",
+				Author:  "Gary Gregory <ggregory@apache.org>",
+				Authors: []Author{
+					{Name: "Gary Gregory", Email: "ggregory@apache.org", Role: AuthorRoleAuthor},
+					{Name: "The Apache Software Foundation", URL: "https://www.apache.org/", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "Apache-2.0",
+				LicenseContent: "Apache License\nVersion 2.0, January 2004",
+				Description:    "Apache Commons Lang, a package of Java utility classes.",
+				Homepage:       "https://commons.apache.org/proper/commons-lang/",
+				Repository:     "https://gitbox.apache.org/repos/asf?p=commons-lang.git",
+				Bugs:           "https://issues.apache.org/jira/browse/LANG",
+			},
+		},
+		{
+			name:    "jar without maven metadata",
+			archive: "demo.jar",
+			module:  Module{Lang: "java", Name: "noinfo", Version: "1.0-SNAPSHOT", Path: "/BOOT-INF/lib/noinfo-1.0-SNAPSHOT.jar"},
+			want: Module{
+				Lang:           "java",
+				Name:           "noinfo",
+				Version:        "1.0-SNAPSHOT",
+				Path:           "/BOOT-INF/lib/noinfo-1.0-SNAPSHOT.jar",
+				Author:         "noinfo authors",
+				LicenseName:    "MIT",
+				LicenseContent: "Permission is hereby granted, free of charge, to any person",
+			},
+		},
+		{
+			name:    "shaded jar",
+			archive: "shaded.jar",
+			module:  Module{Lang: "java", Name: "com.google.code.gson:gson", Version: "2.8.9", Path: "/META-INF/maven/com.google.code.gson/gson"},
+			want: Module{
+				Lang:        "java",
+				Name:        "com.google.code.gson:gson",
+				Version:     "2.8.9",
+				Path:        "/META-INF/maven/com.google.code.gson/gson",
+				Author:      "com.google.code.gson:gson authors",
+				LicenseName: "Apache-2.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, filepath.Join("testdata", "java", tt.archive)))
+			assert.Equal(t, tt.want, module)
+
+			// the archive opened once is shared by the modules
+			archive, err := OpenZipFS(DirFS(filepath.Join("testdata", "java")), tt.archive)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer archive.Close()
+			module = tt.module
+			assert.NoError(t, ReadProjectDataFS(&module, archive, "."))
+			assert.Equal(t, tt.want, module)
+		})
+	}
+}
+
+func Test_javaLicenseName(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"The Apache Software License, Version 2.0", "http://www.apache.org/licenses/LICENSE-2.0.txt", "Apache-2.0"},
+		{"", "https://www.apache.org/licenses/LICENSE-2.0", "Apache-2.0"},
+		{"Eclipse Public License - v 2.0", "", "EPL-2.0"},
+		{"GNU Lesser General Public License, version 2.1", "", "LGPL-2.1"},
+		{"MIT", "", "MIT"},
+		{"The MIT License", "", "MIT"},
+		{"Limited Use License", "", "Limited Use License"},
+		{"", "https://example.com/license", "https://example.com/license"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, javaLicenseName(tt.name, tt.url))
+		})
+	}
+}
+
+func Test_parseJarFileName(t *testing.T) {
+	tests := []struct {
+		file, name, version string
+	}{
+		{"spring-core-5.3.9.jar", "spring-core", "5.3.9"},
+		{"jakarta.annotation-api-1.3.5.jar", "jakarta.annotation-api", "1.3.5"},
+		{"noinfo-1.0-SNAPSHOT.jar", "noinfo", "1.0-SNAPSHOT"},
+		{"tools.jar", "tools", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			name, version := parseJarFileName(tt.file)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func Test_javaPURL(t *testing.T) {
+	m := Module{Lang: "java", Name: "org.apache.commons:commons-lang3", Version: "3.12.0"}
+	assert.Equal(t, "pkg:maven/org.apache.commons/commons-lang3@3.12.0", m.PURL())
+	assert.True(t, strings.HasPrefix((Module{Lang: "java", Name: "noinfo"}).PURL(), "pkg:maven/noinfo"))
+}
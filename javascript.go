@@ -5,7 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,13 +18,19 @@ type sourceMap struct {
 }
 
 func ParseJSSourcemapFile(path string) ([]Module, error) {
+	return ParseJSSourcemapFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseJSSourcemapFS is ParseJSSourcemapFile for the file in the file system.
+func ParseJSSourcemapFS(fsys fs.FS, name string) ([]Module, error) {
 	result := []Module{}
 	tmp := make(map[string]Module)
 
-	f, err := os.Open(path)
+	f, err := fsys.Open(name)
 	if err != nil {
 		return result, err
 	}
+	defer f.Close()
 	dec := json.NewDecoder(f)
 	var sm sourceMap
 	dec.Decode(&sm)
@@ -51,14 +58,6 @@ func ParseJSSourcemapFile(path string) ([]Module, error) {
 				modulePath = filepath.Join(dir, child)
 			}
 			if filepath.IsAbs(modulePath) {
-				root, err := filepath.Abs(path)
-				if err != nil {
-					return nil, err
-				}
-				modulePath, err = filepath.Rel(root, modulePath)
-				if err != nil {
-					return nil, err
-				}
 				if strings.Contains(modulePath, "node_modules") {
 					result := strings.SplitN(modulePath, "node_modules", 2)
 					if len(result) == 2 {
@@ -89,13 +88,19 @@ func ParseJSSourcemapFile(path string) ([]Module, error) {
 }
 
 func ParseJSWebPack(path string) ([]Module, error) {
+	return ParseJSWebPackFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseJSWebPackFS is ParseJSWebPack for the file in the file system.
+func ParseJSWebPackFS(fsys fs.FS, name string) ([]Module, error) {
 	result := []Module{}
 
 	comments := []string{}
-	f, err := os.Open(path)
+	f, err := fsys.Open(name)
 	if err != nil {
 		return result, err
 	}
+	defer f.Close()
 	scanner := bufio.NewScanner(f)
 	bufLen := bufio.MaxScanTokenSize
 	scanner.Buffer(make([]byte, bufLen, 1000*bufLen), 1000*bufLen)
@@ -204,18 +209,28 @@ func parseJSModulePath(modulePath string) *Module {
 }
 
 func projectJSConfigReader(module *Module, root string) error {
-	f, err := os.Open(filepath.Join(root, module.Path, "package.json"))
+	return projectJSConfigReaderFS(module, DirFS(root), ".")
+}
+
+// projectJSConfigReaderFS reads package.json of the module. root is the project folder in the file system.
+func projectJSConfigReaderFS(module *Module, fsys fs.FS, root string) error {
+	f, err := fsys.Open(fsPath(root, module.Path, "package.json"))
 	if err != nil {
 		return err
 	}
+	defer f.Close()
+	// the license banner of the bundle is used only if the package doesn't have the license file
+	bannerContent := module.LicenseContent
+	module.LicenseContent = ""
 	d := json.NewDecoder(f)
 	j := make(map[string]interface{})
 	d.Decode(&j)
-	author, err := projectJSParseAuthor(j)
+	author, authors, err := projectJSParseAuthor(j)
 	if err != nil {
 		return err
 	}
 	module.Author = author
+	module.Authors = authors
 
 	l, ok := j["license"]
 	if !ok {
@@ -240,44 +255,247 @@ func projectJSConfigReader(module *Module, root string) error {
 			lname = fmt.Sprintf("%v", l)
 		}
 		module.LicenseName = lname
+		if file, ok := projectJSSeeLicenseIn(lname); ok {
+			// "SEE LICENSE IN <file>" points to the license text bundled in the package
+			module.LicenseName = licenseRefID(module.Name, file)
+			module.LicenseFile = file
+			content, err := fs.ReadFile(fsys, fsPath(root, module.Path, file))
+			if err == nil {
+				module.LicenseContent = strings.TrimSpace(string(content))
+			} else {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+			}
+		} else if strings.EqualFold(strings.TrimSpace(lname), UnlicensedLicenseName) {
+			module.LicenseName = UnlicensedLicenseName
+			module.Proprietary = true
+		}
 	}
 
-	err = module.readLicense(root)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+	if module.LicenseContent == "" {
+		err = module.readLicenseFS(fsys, root)
+		if err != nil && bannerContent != "" {
+			module.LicenseContent = bannerContent
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
 	}
 
 	module.Version = j["version"].(string)
+	module.Description, _ = j["description"].(string)
+	module.Homepage, _ = j["homepage"].(string)
+	module.Repository = normalizeJSRepositoryURL(j["repository"])
+	module.Bugs = projectJSParseBugs(j["bugs"])
+	module.Funding = projectJSParseFunding(j["funding"])
+
+	if pkg, ok := jsLockfilePackageOf(module, fsys, root); ok {
+		module.Integrity = pkg.Integrity
+	}
 
 	return nil
 }
 
-func projectJSParseAuthor(content map[string]interface{}) (string, error) {
-	author, ok := content["author"]
-	if ok {
-		switch val := author.(type) {
-		case string:
-			return val, nil
-		case map[string]interface{}:
-			email, hasEmail := val["email"]
-			name, hasName := val["name"]
-			if hasName && hasEmail {
-				return name.(string) + " <" + email.(string) + ">", nil
-			} else if hasName {
-				return name.(string), nil
-			} else {
-				log.Println(val)
-				return fmt.Sprintf("%s", val), nil
+// projectJSSeeLicenseIn returns the file name of "SEE LICENSE IN <file>". The file must be in the package folder.
+func projectJSSeeLicenseIn(license string) (string, bool) {
+	const prefix = "SEE LICENSE IN "
+	license = strings.TrimSpace(license)
+	if len(license) <= len(prefix) || !strings.EqualFold(license[:len(prefix)], prefix) {
+		return "", false
+	}
+	return packageFilePath(strings.TrimSpace(license[len(prefix):]))
+}
+
+func projectJSParseBugs(bugs interface{}) string {
+	switch val := bugs.(type) {
+	case string:
+		return val
+	case map[string]interface{}:
+		if u, ok := val["url"].(string); ok && u != "" {
+			return u
+		}
+		if email, ok := val["email"].(string); ok && email != "" {
+			return "mailto:" + email
+		}
+	}
+	return ""
+}
+
+// projectJSParseFunding parses "funding" field. It accepts URL string, {"type", "url"} object or array of them.
+func projectJSParseFunding(funding interface{}) []Funding {
+	var result []Funding
+	switch val := funding.(type) {
+	case string:
+		result = append(result, Funding{URL: val})
+	case map[string]interface{}:
+		u, _ := val["url"].(string)
+		if u != "" {
+			t, _ := val["type"].(string)
+			result = append(result, Funding{Type: t, URL: u})
+		}
+	case []interface{}:
+		for _, f := range val {
+			result = append(result, projectJSParseFunding(f)...)
+		}
+	}
+	return result
+}
+
+func projectJSParseAuthor(content map[string]interface{}) (string, []Author, error) {
+	var authors []Author
+	if author, ok := projectJSParsePerson(content["author"], AuthorRoleAuthor); ok {
+		authors = append(authors, author)
+	}
+	for _, key := range []string{"contributors", "maintainers"} {
+		role := AuthorRoleContributor
+		if key == "maintainers" {
+			role = AuthorRoleMaintainer
+		}
+		people, _ := content[key].([]interface{})
+		for _, person := range people {
+			if author, ok := projectJSParsePerson(person, role); ok {
+				authors = append(authors, author)
 			}
 		}
 	}
-	name, ok := content["name"]
+	if owner, ok := projectJSRepositoryOwner(content["repository"]); ok {
+		authors = append(authors, owner)
+	}
+
+	if len(authors) > 0 && authors[0].Role == AuthorRoleAuthor {
+		return authors[0].displayName(), authors, nil
+	}
+	for _, author := range authors {
+		if author.Role == AuthorRoleOwner {
+			return author.displayName(), authors, nil
+		}
+	}
+	name, ok := content["name"].(string)
 	if !ok {
-		return "", errors.New("not implemented")
+		return "", nil, errors.New("not implemented")
+	}
+	return name + " authors", authors, nil
+}
+
+// projectJSParsePerson parses "people field" of package.json.
+// It accepts "Name <email> (url)" string and {"name", "email", "url"} object.
+func projectJSParsePerson(src interface{}, role string) (Author, bool) {
+	var result Author
+	switch val := src.(type) {
+	case string:
+		result = parseAuthorString(val)
+	case map[string]interface{}:
+		result.Name, _ = val["name"].(string)
+		result.Email, _ = val["email"].(string)
+		result.URL, _ = val["url"].(string)
+		if result.URL == "" {
+			result.URL, _ = val["web"].(string)
+		}
+	default:
+		return result, false
+	}
+	result.Name = strings.TrimSpace(result.Name)
+	result.Email = strings.TrimSpace(result.Email)
+	result.URL = strings.TrimSpace(result.URL)
+	if result.Name == "" && result.Email == "" && result.URL == "" {
+		return result, false
+	}
+	result.Role = role
+	return result, true
+}
+
+// parseAuthorString parses "Name <email> (url)" form. Email and url are optional.
+func parseAuthorString(src string) Author {
+	var result Author
+	if i := strings.Index(src, "("); i != -1 {
+		if j := strings.Index(src[i:], ")"); j != -1 {
+			result.URL = src[i+1 : i+j]
+			src = src[:i] + src[i+j+1:]
+		}
+	}
+	if i := strings.Index(src, "<"); i != -1 {
+		if j := strings.Index(src[i:], ">"); j != -1 {
+			result.Email = src[i+1 : i+j]
+			src = src[:i] + src[i+j+1:]
+		}
+	}
+	result.Name = strings.TrimSpace(src)
+	return result
+}
+
+// projectJSRepositoryOwner returns the user or organization that hosts the repository.
+func projectJSRepositoryOwner(repository interface{}) (Author, bool) {
+	repoURL := normalizeJSRepositoryURL(repository)
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" {
+		return Author{}, false
+	}
+	fragments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(fragments) < 2 || fragments[0] == "" {
+		return Author{}, false
 	}
-	return name.(string) + " authors", nil
+	return Author{
+		Name: fragments[0],
+		URL:  u.Scheme + "://" + u.Host + "/" + fragments[0],
+		Role: AuthorRoleOwner,
+	}, true
+}
+
+var jsRepositoryHosts = map[string]string{
+	"github":    "https://github.com/",
+	"gitlab":    "https://gitlab.com/",
+	"bitbucket": "https://bitbucket.org/",
+	"gist":      "https://gist.github.com/",
+}
+
+// normalizeJSRepositoryURL converts "repository" field of package.json into browsable https URL.
+// It supports shorthands ("user/repo", "github:user/repo") and git URLs ("git+ssh://git@github.com/user/repo.git").
+func normalizeJSRepositoryURL(repository interface{}) string {
+	var src string
+	switch val := repository.(type) {
+	case string:
+		src = val
+	case map[string]interface{}:
+		src, _ = val["url"].(string)
+	}
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return ""
+	}
+	if i := strings.Index(src, ":"); i != -1 {
+		if host, ok := jsRepositoryHosts[src[:i]]; ok {
+			return host + strings.TrimSuffix(src[i+1:], ".git")
+		}
+	}
+	if !strings.Contains(src, ":") && strings.Count(src, "/") == 1 {
+		return jsRepositoryHosts["github"] + src
+	}
+	src = strings.TrimPrefix(src, "git+")
+	if strings.HasPrefix(src, "git@") {
+		// scp-like syntax: git@github.com:user/repo.git
+		src = "ssh://" + strings.Replace(src, ":", "/", 1)
+	}
+	u, err := url.Parse(src)
+	if err != nil || u.Host == "" {
+		return src
+	}
+	return "https://" + u.Hostname() + strings.TrimSuffix(u.Path, ".git")
+}
+
+func jsPURL(module Module) PURL {
+	result := PURL{
+		Type:    "npm",
+		Name:    module.Name,
+		Version: module.Version,
+	}
+	if strings.HasPrefix(module.Name, "@") {
+		if scope, name, ok := strings.Cut(module.Name, "/"); ok {
+			result.Namespace = scope
+			result.Name = name
+		}
+	}
+	return result
 }
 
 func init() {
-	RegisterProjectDataReader("js", projectJSConfigReader)
+	RegisterProjectDataReaderFS("js", projectJSConfigReaderFS)
+	RegisterPURLBuilder("js", jsPURL)
 }
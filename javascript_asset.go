@@ -0,0 +1,213 @@
+package linkedpackage
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Asset types of the files that bundlers copy from the packages besides scripts.
+const (
+	AssetTypeStyle = "style"
+	AssetTypeFont  = "font"
+	AssetTypeImage = "image"
+)
+
+// assetExtensions are the extensions of the files that bundlers copy into the output folder as is.
+var assetExtensions = map[string]string{
+	".woff":  AssetTypeFont,
+	".woff2": AssetTypeFont,
+	".ttf":   AssetTypeFont,
+	".otf":   AssetTypeFont,
+	".eot":   AssetTypeFont,
+	".svg":   AssetTypeImage,
+	".png":   AssetTypeImage,
+	".gif":   AssetTypeImage,
+	".jpg":   AssetTypeImage,
+	".jpeg":  AssetTypeImage,
+	".webp":  AssetTypeImage,
+}
+
+// AssetExtensions returns the extensions of font and image files that JSAssetIndex can find.
+func AssetExtensions() []string {
+	result := make([]string, 0, len(assetExtensions))
+	for extension := range assetExtensions {
+		result = append(result, extension)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// addAssetType adds the asset type to the module if it doesn't have it yet.
+func (m *Module) addAssetType(assetType string) {
+	for _, existing := range m.AssetTypes {
+		if existing == assetType {
+			return
+		}
+	}
+	// copy not to modify the slice that other modules share
+	assetTypes := append(append([]string{}, m.AssetTypes...), assetType)
+	sort.Strings(assetTypes)
+	m.AssetTypes = assetTypes
+}
+
+func ParseCSSSourcemapFile(path string) ([]Module, error) {
+	return ParseCSSSourcemapFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseCSSSourcemapFS reads the sourcemap of the stylesheet (.css.map). Sources in node_modules are returned as
+// the modules that have AssetTypeStyle. Unlike scripts, css-loader and Vite write the sources relative to the map
+// (e.g. "../../node_modules/bootstrap/scss/_root.scss") or with webpack's namespace
+// (e.g. "webpack://app/./node_modules/@fontsource/roboto/index.css").
+func ParseCSSSourcemapFS(fsys fs.FS, name string) ([]Module, error) {
+	result := []Module{}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return result, err
+	}
+	var sm sourceMap
+	json.Unmarshal(content, &sm)
+
+	tmp := make(map[string]Module)
+	for _, source := range sm.Sources {
+		modulePath := source
+		if strings.HasPrefix(modulePath, "webpack://") {
+			modulePath = strings.TrimPrefix(modulePath, "webpack://")
+			if i := strings.Index(modulePath, "/"); i != -1 {
+				modulePath = modulePath[i:]
+			}
+			modulePath = strings.TrimPrefix(modulePath, "/.")
+		}
+		if !strings.HasPrefix(modulePath, "/") {
+			// relative paths go up to the project folder
+			i := strings.Index(modulePath, "node_modules/")
+			if i == -1 {
+				continue
+			}
+			modulePath = "/" + modulePath[i:]
+		}
+		for _, module := range parseJSModulePaths(modulePath) {
+			module.addAssetType(AssetTypeStyle)
+			tmp[module.Name] = module
+		}
+	}
+	for _, v := range tmp {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// JSAssetIndex finds the packages of font and image files that bundlers copied into the output folder.
+// Bundlers rename the files with content hashes, so they are compared with the files in node_modules by content.
+type JSAssetIndex struct {
+	fsys fs.FS
+	root string
+	// files are slash separated paths in fsys grouped by file size
+	files  map[int64][]string
+	hashes map[string][sha256.Size]byte
+}
+
+func NewJSAssetIndex(root string) (*JSAssetIndex, error) {
+	return NewJSAssetIndexFS(DirFS(root), ".")
+}
+
+// NewJSAssetIndexFS indexes font and image files in node_modules of the project folder root.
+func NewJSAssetIndexFS(fsys fs.FS, root string) (*JSAssetIndex, error) {
+	index := &JSAssetIndex{
+		fsys:   fsys,
+		root:   root,
+		files:  map[int64][]string{},
+		hashes: map[string][sha256.Size]byte{},
+	}
+	err := fs.WalkDir(fsys, fsPath(root, "node_modules"), func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if _, ok := assetExtensions[strings.ToLower(path.Ext(name))]; !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		index.files[info.Size()] = append(index.files[info.Size()], name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// hash returns the hash of the indexed file. Hashes are calculated only when the assets have the same size.
+func (index *JSAssetIndex) hash(name string) ([sha256.Size]byte, error) {
+	if h, ok := index.hashes[name]; ok {
+		return h, nil
+	}
+	content, err := fs.ReadFile(index.fsys, name)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.Sum256(content)
+	index.hashes[name] = h
+	return h, nil
+}
+
+func (index *JSAssetIndex) Find(path string) ([]Module, error) {
+	return index.FindFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// FindFS returns the packages that have the same file as the asset. The modules have AssetTypeFont or
+// AssetTypeImage. It returns an empty slice if the asset is not copied from node_modules.
+func (index *JSAssetIndex) FindFS(fsys fs.FS, name string) ([]Module, error) {
+	result := []Module{}
+	assetType, ok := assetExtensions[strings.ToLower(path.Ext(name))]
+	if !ok {
+		return result, nil
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return result, err
+	}
+	candidates := index.files[info.Size()]
+	if len(candidates) == 0 {
+		return result, nil
+	}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return result, err
+	}
+	h := sha256.Sum256(content)
+	found := map[string]bool{}
+	for _, candidate := range candidates {
+		ch, err := index.hash(candidate)
+		if err != nil || h != ch {
+			continue
+		}
+		rel := candidate
+		if index.root != "." {
+			rel = strings.TrimPrefix(candidate, index.root+"/")
+		}
+		module := parseJSModulePath("/" + rel)
+		if module == nil || found[module.Path] {
+			continue
+		}
+		found[module.Path] = true
+		module.addAssetType(assetType)
+		result = append(result, *module)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package linkedpackage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testWebProject = filepath.Join("testdata", "web-project")
+
+func TestParseCSSSourcemapFile(t *testing.T) {
+	modules, err := ParseCSSSourcemapFile(filepath.Join(testWebProject, "dist", "assets", "index.css.map"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeStyle}},
+		{Lang: "js", Name: "bootstrap", Path: "/node_modules/bootstrap", AssetTypes: []string{AssetTypeStyle}},
+	}, modules)
+}
+
+func TestJSAssetIndex_Find(t *testing.T) {
+	index, err := NewJSAssetIndex(testWebProject)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		name  string
+		asset string
+		want  []Module
+	}{
+		{
+			name:  "renamed font",
+			asset: "roboto-latin-400-normal-a3b5c7d9.woff2",
+			want: []Module{
+				{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeFont}},
+			},
+		},
+		{
+			name:  "image",
+			asset: "alarm-0f1e2d3c.svg",
+			want: []Module{
+				{Lang: "js", Name: "bootstrap-icons", Path: "/node_modules/bootstrap-icons", AssetTypes: []string{AssetTypeImage}},
+			},
+		},
+		{
+			name:  "application's own file",
+			asset: "logo.svg",
+			want:  []Module{},
+		},
+		{
+			name:  "not an asset",
+			asset: "index.css",
+			want:  []Module{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := index.Find(filepath.Join(testWebProject, "dist", "assets", tt.asset))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, modules)
+		})
+	}
+}
+
+func TestUniqueModules_assetTypes(t *testing.T) {
+	modules := UniqueModules([]Module{
+		{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeStyle}},
+		{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeFont}},
+		{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeStyle}},
+	})
+	assert.Equal(t, []Module{
+		{Lang: "js", Name: "@fontsource/roboto", Path: "/node_modules/@fontsource/roboto", AssetTypes: []string{AssetTypeFont, AssetTypeStyle}},
+	}, modules)
+}
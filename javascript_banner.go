@@ -0,0 +1,230 @@
+package linkedpackage
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Minifiers (terser, esbuild, cssnano) keep "/*! ... */" comments and comments that have @license or @preserve,
+// and terser's extractComments option moves them into "<chunk>.LICENSE.txt". These banners identify packages
+// in production builds that don't have sourcemaps or webpack's module headers.
+
+var (
+	// bannerTitlePattern matches the first line of the banner like "Bootstrap v5.3.0 (https://getbootstrap.com/)"
+	// or "normalize.css v8.0.1 | MIT License". Up to three words are joined with "-" (e.g. "Bootstrap Icons").
+	bannerTitlePattern = regexp.MustCompile(`^(@?[A-Za-z0-9][\w.\-]*(?:/[\w.\-]+)?(?: [A-Za-z][\w.\-]*){0,2})\s+v?(\d+\.\d+(?:\.\d+)?[0-9A-Za-z.\-+]*)`)
+	// bannerPackagePattern matches "name@version" form like "vue@3.3.4 | MIT".
+	bannerPackagePattern = regexp.MustCompile(`^(@?[A-Za-z0-9][\w.\-]*(?:/[\w.\-]+)?)@(\d+\.\d+\.\d+[0-9A-Za-z.\-+]*)`)
+	bannerVersionPattern = regexp.MustCompile(`^v\d+\.\d+`)
+	// bannerFilePattern matches the bundle file name in the banner like "react-dom.production.min.js".
+	bannerFilePattern = regexp.MustCompile(`^([a-z0-9][\w\-]*(?:\.[a-z][\w\-]*)*?)(?:\.(?:production|development|profiling))?(?:\.min)?\.js$`)
+	// spdxPattern matches common SPDX license identifiers and expressions.
+	spdxPattern         = regexp.MustCompile(`(?i)^\(?(MIT|ISC|0BSD|Zlib|WTFPL|Unlicense|BSD-[\w.\-]+|Apache-[\d.]+|A?GPL-[\w.\-+]+|LGPL-[\w.\-+]+|MPL-[\d.]+|CC0-[\d.]+|CC-BY-[\w.\-]+|OFL-[\d.]+|BlueOak-[\d.]+|Artistic-[\d.]+|Python-[\d.]+)\)?$`)
+	bannerLicensedUnder = regexp.MustCompile(`(?i)licen[sc]ed under (?:the )?(\(?[\w.\-+]+\)?)`)
+	bannerLicenseSuffix = regexp.MustCompile(`(?i)(?:^|[\s|(])([\w.\-+]+) licen[sc]e\b`)
+	// bannerCopyright matches "Copyright (c) 2011-2023 Holder" and "| (c) Holder |".
+	bannerCopyright      = regexp.MustCompile(`(?i)(?:^copyright\s*(?:\(c\)|©)?|\(c\)|©)\s*(?:\d{4}(?:\s*[-–]\s*(?:\d{4}|present))?[,.]?\s*)*([^|<]+)`)
+	bannerLicenseKeyword = regexp.MustCompile(`(?i)licen[sc]e|copyright|@preserve|\(c\)|©`)
+)
+
+// licenseComments returns the bodies of the comments that minifiers keep. If all is true, all comments are returned
+// (for .LICENSE.txt files).
+func licenseComments(content string, all bool) []string {
+	var result []string
+	for {
+		i := strings.Index(content, "/*")
+		if i == -1 {
+			return result
+		}
+		content = content[i+2:]
+		end := strings.Index(content, "*/")
+		if end == -1 {
+			return result
+		}
+		comment := content[:end]
+		content = content[end+2:]
+		if all || strings.HasPrefix(comment, "!") || strings.Contains(comment, "@license") || strings.Contains(comment, "@preserve") {
+			result = append(result, comment)
+		}
+	}
+}
+
+// bannerText returns the text of the comment without leading "*" of each line.
+func bannerText(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*!"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// bannerLines returns the lines of the comment without leading "*" and spaces. Empty lines are removed.
+func bannerLines(comment string) []string {
+	var result []string
+	for _, line := range strings.Split(bannerText(comment), "\n") {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// parseBannerTitle returns the package name and version of the banner.
+func parseBannerTitle(comment string) (name, version string, ok bool) {
+	lines := bannerLines(comment)
+	if len(lines) == 0 {
+		return "", "", false
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "@license ") {
+			continue
+		}
+		// "@license React v16.13.1" and "react-dom.production.min.js" in the next line
+		fields := strings.Fields(strings.TrimPrefix(line, "@license "))
+		if len(fields) == 0 || spdxPattern.MatchString(fields[0]) {
+			break
+		}
+		name = fields[0]
+		if len(fields) > 1 && bannerVersionPattern.MatchString(fields[1]) {
+			version = strings.TrimPrefix(fields[1], "v")
+		}
+		if i+1 < len(lines) {
+			if match := bannerFilePattern.FindStringSubmatch(lines[i+1]); match != nil {
+				name = match[1]
+			}
+		}
+		return strings.ToLower(name), version, true
+	}
+	if match := bannerPackagePattern.FindStringSubmatch(lines[0]); match != nil {
+		return match[1], match[2], true
+	}
+	match := bannerTitlePattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return "", "", false
+	}
+	return strings.ToLower(strings.ReplaceAll(match[1], " ", "-")), match[2], true
+}
+
+// parseBannerLicense returns SPDX identifier of the banner like "@license MIT", "Licensed under MIT" or "MIT License".
+func parseBannerLicense(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "@license ") {
+			license := strings.TrimSpace(strings.TrimPrefix(line, "@license "))
+			if spdxPattern.MatchString(license) || strings.Contains(license, " OR ") || strings.Contains(license, " AND ") {
+				return strings.Trim(license, "()")
+			}
+		}
+	}
+	if match := bannerLicensedUnder.FindStringSubmatch(text); match != nil && spdxPattern.MatchString(match[1]) {
+		return strings.Trim(match[1], "()")
+	}
+	for _, match := range bannerLicenseSuffix.FindAllStringSubmatch(text, -1) {
+		if spdxPattern.MatchString(match[1]) {
+			return match[1]
+		}
+	}
+	return guessLicenseName(text)
+}
+
+// parseBannerAuthor returns the author of "@author" tag or the first copyright holder.
+func parseBannerAuthor(lines []string) (Author, bool) {
+	for _, line := range lines {
+		if strings.HasPrefix(line, "@author ") {
+			author := parseAuthorString(strings.TrimSpace(strings.TrimPrefix(line, "@author ")))
+			author.Role = AuthorRoleAuthor
+			return author, true
+		}
+	}
+	for _, line := range lines {
+		if match := bannerCopyright.FindStringSubmatch(line); match != nil {
+			holder := strings.TrimSpace(strings.TrimRight(match[1], "."))
+			if holder != "" {
+				return Author{Name: holder, Role: AuthorRoleAuthor}, true
+			}
+		}
+	}
+	return Author{}, false
+}
+
+// parseBannerModule creates the module from the banner. The banner is used as the license text.
+// Banners that don't mention licenses or copyrights (e.g. "Built with webpack 5.88.0") are ignored.
+func parseBannerModule(comment string) (Module, bool) {
+	text := bannerText(comment)
+	if !bannerLicenseKeyword.MatchString(text) {
+		return Module{}, false
+	}
+	name, version, ok := parseBannerTitle(comment)
+	if !ok {
+		return Module{}, false
+	}
+	module := Module{
+		Lang:           "js",
+		Name:           name,
+		Path:           "/node_modules/" + name,
+		Version:        version,
+		LicenseName:    parseBannerLicense(text),
+		LicenseContent: text,
+	}
+	if author, ok := parseBannerAuthor(bannerLines(comment)); ok {
+		module.Author = author.displayName()
+		module.Authors = []Author{author}
+	}
+	return module, true
+}
+
+func parseBannerModules(content string, all bool, assetType string) []Module {
+	result := []Module{}
+	tmp := make(map[string]Module)
+	for _, comment := range licenseComments(content, all) {
+		module, ok := parseBannerModule(comment)
+		if !ok {
+			continue
+		}
+		if assetType != "" {
+			module.addAssetType(assetType)
+		}
+		if _, ok := tmp[module.Name]; !ok {
+			tmp[module.Name] = module
+		}
+	}
+	for _, v := range tmp {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func ParseCSSBanner(path string) ([]Module, error) {
+	return ParseCSSBannerFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseCSSBannerFS reads "/*! ... */" banners of the stylesheet. The modules have AssetTypeStyle.
+// See ParseJSBannerFS about the results.
+func ParseCSSBannerFS(fsys fs.FS, name string) ([]Module, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return []Module{}, err
+	}
+	return parseBannerModules(string(content), false, AssetTypeStyle), nil
+}
+
+func ParseJSBanner(path string) ([]Module, error) {
+	return ParseJSBannerFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseJSBannerFS reads license banners of the minified script or all comments of ".LICENSE.txt" file that terser
+// extracted. The package name is guessed from the banner, so ReadProjectData may not find the package.json of
+// the returned modules. The modules have the banner as LicenseContent and the license name and the author
+// in the banner. They are used when the package is not installed.
+func ParseJSBannerFS(fsys fs.FS, name string) ([]Module, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return []Module{}, err
+	}
+	return parseBannerModules(string(content), strings.HasSuffix(name, ".LICENSE.txt"), ""), nil
+}
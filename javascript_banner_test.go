@@ -0,0 +1,181 @@
+package linkedpackage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testBannerProject = filepath.Join("testdata", "banner-project")
+
+func TestParseJSBanner(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []Module
+	}{
+		{
+			name: "banners in minified script",
+			file: "main.js",
+			want: []Module{
+				{
+					Lang:           "js",
+					Name:           "jquery",
+					Path:           "/node_modules/jquery",
+					Author:         "OpenJS Foundation and other contributors",
+					Authors:        []Author{{Name: "OpenJS Foundation and other contributors", Role: AuthorRoleAuthor}},
+					LicenseName:    "unknown",
+					LicenseContent: "jQuery v3.7.0 | (c) OpenJS Foundation and other contributors | jquery.org/license",
+					Version:        "3.7.0",
+				},
+			},
+		},
+		{
+			name: "comments extracted by terser",
+			file: "main.js.LICENSE.txt",
+			want: []Module{
+				{
+					Lang:           "js",
+					Name:           "preact",
+					Path:           "/node_modules/preact",
+					Author:         "Jason Miller",
+					Authors:        []Author{{Name: "Jason Miller", Role: AuthorRoleAuthor}},
+					LicenseName:    "MIT",
+					LicenseContent: "@license Preact v10.15.1\nCopyright (c) 2015-present Jason Miller\nReleased under the MIT License.",
+					Version:        "10.15.1",
+				},
+				{
+					Lang:           "js",
+					Name:           "react-dom",
+					Path:           "/node_modules/react-dom",
+					Author:         "Facebook, Inc. and its affiliates",
+					Authors:        []Author{{Name: "Facebook, Inc. and its affiliates", Role: AuthorRoleAuthor}},
+					LicenseName:    "MIT",
+					LicenseContent: "@license React\nreact-dom.production.min.js\n\nCopyright (c) Facebook, Inc. and its affiliates.\n\nThis source code is licensed under the MIT license found in the\nLICENSE file in the root directory of this source tree.",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := ParseJSBanner(filepath.Join(testBannerProject, "dist", tt.file))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, modules)
+		})
+	}
+}
+
+func Test_projectJSConfigReader_banner(t *testing.T) {
+	modules, err := ParseJSBanner(filepath.Join(testBannerProject, "dist", "main.js.LICENSE.txt"))
+	if !assert.NoError(t, err) || !assert.Len(t, modules, 2) {
+		return
+	}
+	// the license file of the installed package is used instead of the banner
+	module := modules[1]
+	assert.NoError(t, ReadProjectData(&module, testBannerProject))
+	assert.Equal(t, "18.2.0", module.Version)
+	assert.Equal(t, "MIT License\n\nCopyright (c) Facebook, Inc. and its affiliates.", module.LicenseContent)
+
+	// the banner is kept if the package doesn't have the license file
+	fsys := NewMemFS(map[string][]byte{
+		"node_modules/react-dom/package.json": []byte(`{"name": "react-dom", "version": "18.2.0", "license": "MIT"}`),
+	})
+	module = modules[1]
+	assert.NoError(t, ReadProjectDataFS(&module, fsys, "."))
+	assert.Equal(t, "18.2.0", module.Version)
+	assert.Equal(t, modules[1].LicenseContent, module.LicenseContent)
+
+	// not installed
+	module = modules[0]
+	assert.Error(t, ReadProjectData(&module, testBannerProject))
+}
+
+func TestParseCSSBanner(t *testing.T) {
+	modules, err := ParseCSSBanner(filepath.Join(testWebProject, "dist", "assets", "index.css"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{
+			Lang:           "js",
+			Name:           "bootstrap",
+			Path:           "/node_modules/bootstrap",
+			Author:         "The Bootstrap Authors",
+			Authors:        []Author{{Name: "The Bootstrap Authors", Role: AuthorRoleAuthor}},
+			LicenseName:    "MIT",
+			LicenseContent: "Bootstrap  v5.3.0 (https://getbootstrap.com/)\nCopyright 2011-2023 The Bootstrap Authors\nLicensed under MIT (https://github.com/twbs/bootstrap/blob/main/LICENSE)",
+			Version:        "5.3.0",
+			AssetTypes:     []string{AssetTypeStyle},
+		},
+	}, modules)
+}
+
+func Test_parseBannerTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		comment     string
+		wantName    string
+		wantVersion string
+		wantOk      bool
+	}{
+		{
+			name:        "multi-line banner",
+			comment:     "\n * Bootstrap v5.3.0 (https://getbootstrap.com/)\n * Copyright 2011-2023 The Bootstrap Authors\n ",
+			wantName:    "bootstrap",
+			wantVersion: "5.3.0",
+			wantOk:      true,
+		},
+		{
+			name:        "one-line banner",
+			comment:     " normalize.css v8.0.1 | MIT License | github.com/necolas/normalize.css ",
+			wantName:    "normalize.css",
+			wantVersion: "8.0.1",
+			wantOk:      true,
+		},
+		{
+			name:        "multiple words",
+			comment:     "\n * Bootstrap Icons v1.10.5 (https://icons.getbootstrap.com/)\n ",
+			wantName:    "bootstrap-icons",
+			wantVersion: "1.10.5",
+			wantOk:      true,
+		},
+		{
+			name:        "name@version",
+			comment:     " vue@3.3.4 | MIT ",
+			wantName:    "vue",
+			wantVersion: "3.3.4",
+			wantOk:      true,
+		},
+		{
+			name:        "@license with the bundle file name",
+			comment:     "*\n * @license React\n * react-dom.production.min.js\n *\n * Copyright (c) Facebook, Inc. and its affiliates.\n ",
+			wantName:    "react-dom",
+			wantVersion: "",
+			wantOk:      true,
+		},
+		{
+			name:        "@license with version",
+			comment:     "*\n * @license Preact v10.15.1\n * Copyright (c) 2015-present Jason Miller\n ",
+			wantName:    "preact",
+			wantVersion: "10.15.1",
+			wantOk:      true,
+		},
+		{
+			name:    "@license with license name",
+			comment: "*\n * @license MIT\n ",
+			wantOk:  false,
+		},
+		{
+			name:    "without version",
+			comment: " Copyright 2011-2023 The Bootstrap Authors ",
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, version, ok := parseBannerTitle(tt.comment)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
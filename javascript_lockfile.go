@@ -0,0 +1,91 @@
+package linkedpackage
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type jsLockfile struct {
+	LockfileVersion int                             `json:"lockfileVersion"`
+	Packages        map[string]jsLockfilePackage    `json:"packages"`
+	Dependencies    map[string]jsLockfileDependency `json:"dependencies"`
+}
+
+// jsLockfilePackage is the entry of "packages" (lockfileVersion 2, 3)
+type jsLockfilePackage struct {
+	Version   string `json:"version"`
+	Resolved  string `json:"resolved"`
+	Integrity string `json:"integrity"`
+}
+
+// jsLockfileDependency is the entry of "dependencies" (lockfileVersion 1)
+type jsLockfileDependency struct {
+	jsLockfilePackage
+	Dependencies map[string]jsLockfileDependency `json:"dependencies"`
+}
+
+var (
+	jsLockfileCache     = map[string]map[string]jsLockfilePackage{}
+	jsLockfileCacheLock sync.Mutex
+)
+
+// readJSLockfile reads package-lock.json (or npm-shrinkwrap.json) in the root folder.
+// The result's key is the install path like "node_modules/a/node_modules/b".
+// Lockfiles of OS folders are cached.
+func readJSLockfile(fsys fs.FS, root string) map[string]jsLockfilePackage {
+	jsLockfileCacheLock.Lock()
+	defer jsLockfileCacheLock.Unlock()
+	cacheKey := ""
+	if dir, ok := fsys.(hostFS); ok {
+		cacheKey = filepath.Join(string(dir), filepath.FromSlash(root))
+		if result, ok := jsLockfileCache[cacheKey]; ok {
+			return result
+		}
+	}
+	result := map[string]jsLockfilePackage{}
+	for _, name := range []string{"npm-shrinkwrap.json", "package-lock.json", "node_modules/.package-lock.json"} {
+		f, err := fsys.Open(fsPath(root, name))
+		if err != nil {
+			continue
+		}
+		var lockfile jsLockfile
+		err = json.NewDecoder(f).Decode(&lockfile)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		if len(lockfile.Packages) > 0 {
+			// lockfileVersion 2, 3
+			for path, pkg := range lockfile.Packages {
+				if path != "" {
+					result[path] = pkg
+				}
+			}
+		} else {
+			// lockfileVersion 1
+			flattenJSLockfileDependencies("", lockfile.Dependencies, result)
+		}
+		break
+	}
+	if cacheKey != "" {
+		jsLockfileCache[cacheKey] = result
+	}
+	return result
+}
+
+func flattenJSLockfileDependencies(parent string, deps map[string]jsLockfileDependency, result map[string]jsLockfilePackage) {
+	for name, dep := range deps {
+		path := parent + "node_modules/" + name
+		result[path] = dep.jsLockfilePackage
+		flattenJSLockfileDependencies(path+"/", dep.Dependencies, result)
+	}
+}
+
+// jsLockfilePackageOf finds the lockfile entry of the module that is installed at module.Path.
+func jsLockfilePackageOf(module *Module, fsys fs.FS, root string) (jsLockfilePackage, bool) {
+	pkg, ok := readJSLockfile(fsys, root)[strings.TrimPrefix(filepath.ToSlash(module.Path), "/")]
+	return pkg, ok
+}
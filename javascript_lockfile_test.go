@@ -0,0 +1,52 @@
+package linkedpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jsLockfilePackageOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		root   string
+		path   string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "lockfileVersion 2",
+			root:   "testdata/lockfile/v2",
+			path:   "/node_modules/foo",
+			want:   "sha512-z4PhNX7vuL3xVChQ1m2AB9Yg5AULVxXcg/SpIdNs6c5H0NE8XYXysP+DGNKHfuwvY7kxvUdBeoGlODJ6+SfaPg==",
+			wantOk: true,
+		},
+		{
+			name:   "lockfileVersion 2 nested package",
+			root:   "testdata/lockfile/v2",
+			path:   "/node_modules/foo/node_modules/bar",
+			want:   "sha1-Ys4HkA5rF9YIHJ3F1M3Hn2Fp7Oc=",
+			wantOk: true,
+		},
+		{
+			name:   "lockfileVersion 1 nested package",
+			root:   "testdata/lockfile/v1",
+			path:   "/node_modules/foo/node_modules/bar",
+			want:   "sha1-Ys4HkA5rF9YIHJ3F1M3Hn2Fp7Oc=",
+			wantOk: true,
+		},
+		{
+			name:   "not installed",
+			root:   "testdata/lockfile/v2",
+			path:   "/node_modules/bar",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := jsLockfilePackageOf(&Module{Lang: "js", Path: tt.path}, DirFS(tt.root), ".")
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got.Integrity)
+		})
+	}
+}
@@ -354,10 +354,13 @@ func Test_projectJSConfigReader(t *testing.T) {
 				root: "testdata/license",
 			},
 			want: &Module{
-				Lang:           "js",
-				Name:           "sample1",
-				Path:           "sample1",
-				Author:         "abc",
+				Lang:   "js",
+				Name:   "sample1",
+				Path:   "sample1",
+				Author: "abc",
+				Authors: []Author{
+					{Name: "abc", Role: AuthorRoleAuthor},
+				},
 				LicenseName:    "MIT",
 				LicenseContent: "MIT",
 				Version:        "1.0.0",
@@ -375,10 +378,13 @@ func Test_projectJSConfigReader(t *testing.T) {
 				root: "testdata/license",
 			},
 			want: &Module{
-				Lang:           "js",
-				Name:           "sample2",
-				Path:           "sample2",
-				Author:         "abc <abc@example.com>",
+				Lang:   "js",
+				Name:   "sample2",
+				Path:   "sample2",
+				Author: "abc <abc@example.com>",
+				Authors: []Author{
+					{Name: "abc", Email: "abc@example.com", Role: AuthorRoleAuthor},
+				},
 				LicenseName:    "MIT",
 				LicenseContent: "MIT",
 				Version:        "1.0.0",
@@ -396,13 +402,128 @@ func Test_projectJSConfigReader(t *testing.T) {
 				root: "testdata/license",
 			},
 			want: &Module{
-				Lang:           "js",
-				Name:           "sample3",
-				Path:           "sample3",
-				Author:         "Kris Zyp",
+				Lang:   "js",
+				Name:   "sample3",
+				Path:   "sample3",
+				Author: "Kris Zyp",
+				Authors: []Author{
+					{Name: "Kris Zyp", Role: AuthorRoleAuthor},
+					{Name: "Kris Zyp", Email: "kriszyp@gmail.com", Role: AuthorRoleMaintainer},
+					{Name: "kriszyp", URL: "https://github.com/kriszyp", Role: AuthorRoleOwner},
+				},
 				LicenseName:    "AFLv2.1, BSD",
 				LicenseContent: "",
 				Version:        "0.2.3",
+				Description:    "JSON Schema validation and specifications",
+				Repository:     "https://github.com/kriszyp/json-schema",
+			},
+			wantErr: false,
+		},
+		{
+			name: "read package.json with SEE LICENSE IN",
+			args: args{
+				module: &Module{
+					Lang: "js",
+					Name: "@acme/ui",
+					Path: "sample4",
+				},
+				root: "testdata/license",
+			},
+			want: &Module{
+				Lang:   "js",
+				Name:   "@acme/ui",
+				Path:   "sample4",
+				Author: "ACME Corp.",
+				Authors: []Author{
+					{Name: "ACME Corp.", Role: AuthorRoleAuthor},
+				},
+				LicenseName:    "LicenseRef-acme-ui-EULA.md",
+				LicenseContent: "ACME END USER LICENSE AGREEMENT",
+				LicenseFile:    "EULA.md",
+				Version:        "2.1.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "read package.json with UNLICENSED",
+			args: args{
+				module: &Module{
+					Lang: "js",
+					Name: "internal-utils",
+					Path: "sample5",
+				},
+				root: "testdata/license",
+			},
+			want: &Module{
+				Lang:   "js",
+				Name:   "internal-utils",
+				Path:   "sample5",
+				Author: "ACME Corp.",
+				Authors: []Author{
+					{Name: "ACME Corp.", Role: AuthorRoleAuthor},
+				},
+				LicenseName: "UNLICENSED",
+				Proprietary: true,
+				Version:     "0.1.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "read integrity from package-lock.json",
+			args: args{
+				module: &Module{
+					Lang: "js",
+					Name: "foo",
+					Path: "/node_modules/foo",
+				},
+				root: "testdata/lockfile/v2",
+			},
+			want: &Module{
+				Lang:   "js",
+				Name:   "foo",
+				Path:   "/node_modules/foo",
+				Author: "foo author",
+				Authors: []Author{
+					{Name: "foo author", Role: AuthorRoleAuthor},
+				},
+				LicenseName:    "MIT",
+				LicenseContent: "MIT",
+				Version:        "1.2.3",
+				Integrity:      "sha512-z4PhNX7vuL3xVChQ1m2AB9Yg5AULVxXcg/SpIdNs6c5H0NE8XYXysP+DGNKHfuwvY7kxvUdBeoGlODJ6+SfaPg==",
+			},
+			wantErr: false,
+		},
+		{
+			name: "read package.json with contributors and repository owner",
+			args: args{
+				module: &Module{
+					Lang: "js",
+					Name: "sample6",
+					Path: "sample6",
+				},
+				root: "testdata/license",
+			},
+			want: &Module{
+				Lang:   "js",
+				Name:   "sample6",
+				Path:   "sample6",
+				Author: "example-org",
+				Authors: []Author{
+					{Name: "Alice", Email: "alice@example.com", URL: "https://alice.example.com", Role: AuthorRoleContributor},
+					{Name: "Bob", URL: "https://bob.example.com", Role: AuthorRoleContributor},
+					{Name: "example-org", URL: "https://github.com/example-org", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "MIT",
+				LicenseContent: "MIT",
+				Version:        "3.0.0",
+				Description:    "sample package with metadata",
+				Homepage:       "https://sample6.example.com",
+				Repository:     "https://github.com/example-org/sample6",
+				Bugs:           "https://github.com/example-org/sample6/issues",
+				Funding: []Funding{
+					{Type: "github", URL: "https://github.com/sponsors/example-org"},
+					{URL: "https://opencollective.com/sample6"},
+				},
 			},
 			wantErr: false,
 		},
@@ -417,6 +538,37 @@ func Test_projectJSConfigReader(t *testing.T) {
 	}
 }
 
+func Test_projectJSSeeLicenseIn(t *testing.T) {
+	tests := []struct {
+		license string
+		want    string
+		wantOk  bool
+	}{
+		{license: "SEE LICENSE IN EULA.md", want: "EULA.md", wantOk: true},
+		{license: "see license in docs/LICENSE.txt", want: "docs/LICENSE.txt", wantOk: true},
+		{license: "MIT"},
+		{license: "SEE LICENSE IN ../../../etc/passwd"},
+		{license: "SEE LICENSE IN /etc/passwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			got, ok := projectJSSeeLicenseIn(tt.license)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+
+	// the file out of the package is not read
+	fsys := NewMemFS(map[string][]byte{
+		"node_modules/evil/package.json": []byte(`{"name": "evil", "version": "1.0.0", "license": "SEE LICENSE IN ../../secret.txt"}`),
+		"secret.txt":                     []byte("secret"),
+	})
+	module := Module{Lang: "js", Name: "evil", Path: "/node_modules/evil"}
+	assert.NoError(t, ReadProjectDataFS(&module, fsys, "."))
+	assert.Empty(t, module.LicenseFile)
+	assert.NotContains(t, module.LicenseContent, "secret")
+}
+
 func TestParseNextJS(t *testing.T) {
 	type args struct {
 		path string
@@ -484,3 +636,92 @@ func TestParseNextJS(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseAuthorString(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Author
+	}{
+		{
+			name: "name only",
+			src:  "Barney Rubble",
+			want: Author{Name: "Barney Rubble"},
+		},
+		{
+			name: "name and email",
+			src:  "Barney Rubble <b@rubble.com>",
+			want: Author{Name: "Barney Rubble", Email: "b@rubble.com"},
+		},
+		{
+			name: "name, email and url",
+			src:  "Barney Rubble <b@rubble.com> (http://barnyrubble.tumblr.com/)",
+			want: Author{Name: "Barney Rubble", Email: "b@rubble.com", URL: "http://barnyrubble.tumblr.com/"},
+		},
+		{
+			name: "name and url",
+			src:  "Barney Rubble (http://barnyrubble.tumblr.com/)",
+			want: Author{Name: "Barney Rubble", URL: "http://barnyrubble.tumblr.com/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseAuthorString(tt.src))
+		})
+	}
+}
+
+func Test_normalizeJSRepositoryURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository interface{}
+		want       string
+	}{
+		{
+			name:       "github shorthand",
+			repository: "github:user/repo",
+			want:       "https://github.com/user/repo",
+		},
+		{
+			name:       "implicit github shorthand",
+			repository: "user/repo",
+			want:       "https://github.com/user/repo",
+		},
+		{
+			name:       "gitlab shorthand",
+			repository: "gitlab:user/repo",
+			want:       "https://gitlab.com/user/repo",
+		},
+		{
+			name: "git+https url",
+			repository: map[string]interface{}{
+				"type": "git",
+				"url":  "git+https://github.com/user/repo.git",
+			},
+			want: "https://github.com/user/repo",
+		},
+		{
+			name: "git+ssh url",
+			repository: map[string]interface{}{
+				"type": "git",
+				"url":  "git+ssh://git@github.com/user/repo.git",
+			},
+			want: "https://github.com/user/repo",
+		},
+		{
+			name:       "scp-like url",
+			repository: "git@github.com:user/repo.git",
+			want:       "https://github.com/user/repo",
+		},
+		{
+			name:       "missing",
+			repository: nil,
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeJSRepositoryURL(tt.repository))
+		})
+	}
+}
@@ -2,35 +2,193 @@ package linkedpackage
 
 import (
 	"bufio"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"sort"
 	"strings"
 )
 
+// UnlicensedLicenseName is the license name of proprietary packages
+// that don't grant any rights to use them.
+const UnlicensedLicenseName = "UNLICENSED"
+
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleContributor = "contributor"
+	AuthorRoleMaintainer  = "maintainer"
+	AuthorRoleOwner       = "repository owner"
+)
+
+// Author is a person or organization that is related to the module.
+type Author struct {
+	Name  string
+	Email string
+	URL   string
+	Role  string
+}
+
+func (a Author) displayName() string {
+	if a.Name != "" && a.Email != "" {
+		return a.Name + " <" + a.Email + ">"
+	} else if a.Name != "" {
+		return a.Name
+	} else if a.Email != "" {
+		return a.Email
+	}
+	return a.URL
+}
+
+// String returns "Name <email> (url)" form that is same as package.json's people field.
+func (a Author) String() string {
+	result := a.displayName()
+	if a.URL != "" && result != a.URL {
+		result += " (" + a.URL + ")"
+	}
+	return result
+}
+
 type Module struct {
-	Lang           string
-	Name           string
-	Path           string
-	Author         string
+	Lang   string
+	Name   string
+	Path   string
+	Author string
+	// Authors contains author, contributors, maintainers and repository owner of the module
+	Authors        []Author
 	LicenseName    string
 	LicenseContent string
-	Version        string
+	// LicenseFile is the file name that package refers as its license (e.g. "SEE LICENSE IN <file>")
+	LicenseFile string
+	// Proprietary is true when the package is not licensed for use by others
+	Proprietary bool
+	Version     string
+	Description string
+	Homepage    string
+	// Repository is browsable URL of the source code repository
+	Repository string
+	// Bugs is URL (or email address) of the issue tracker
+	Bugs    string
+	Funding []Funding
+	// Integrity is Subresource Integrity string of the package archive (e.g. "sha512-...") recorded in the lockfile
+	Integrity string
+	// DirHash is the "h1:" hash of the installed package folder (HashDir, or go.sum's hash of Go modules).
+	// It is calculated only when it is needed (e.g. SBOM) because it reads all files of the package
+	DirHash string
+	// Distro is "<ID>-<VERSION_ID>" of /etc/os-release for OS packages (e.g. "debian-12")
+	Distro string
+	// AssetTypes are the kinds of files other than scripts that the bundle takes from the package
+	// (AssetTypeStyle, AssetTypeFont or AssetTypeImage)
+	AssetTypes []string
+}
+
+// Checksum is the hex encoded hash value.
+type Checksum struct {
+	// Algorithm is upper-case algorithm name like "SHA512"
+	Algorithm string
+	Value     string
+}
+
+// Checksums returns hash values of Integrity. DirHash is not included because it is not the hash of
+// the package file but of the list of file hashes.
+func (m Module) Checksums() []Checksum {
+	var result []Checksum
+	for _, sri := range strings.Fields(m.Integrity) {
+		alg, value, ok := strings.Cut(sri, "-")
+		if !ok {
+			continue
+		}
+		if i := strings.Index(value, "?"); i != -1 {
+			value = value[:i]
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		result = append(result, Checksum{
+			Algorithm: strings.ToUpper(alg),
+			Value:     hex.EncodeToString(decoded),
+		})
+	}
+	return result
+}
+
+// Funding is the way to support the module development.
+type Funding struct {
+	Type string
+	URL  string
+}
+
+// licenseRefID creates SPDX's LicenseRef- identifier for the license file of the module.
+func licenseRefID(moduleName, file string) string {
+	src := strings.TrimPrefix(moduleName, "@") + "-" + filepath.Base(file)
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, src)
+	return "LicenseRef-" + id
+}
+
+// licensePatterns are phrases that identify common license texts. Specific ones come first.
+var licensePatterns = []struct {
+	name    string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "2.0"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-2.1", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+	{"ISC", []string{"Permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"This is free and unencumbered software released into the public domain"}},
+}
+
+// guessLicenseName returns SPDX identifier of the license text for ecosystems that don't have license field in
+// their manifests. It returns "unknown" if the text doesn't match known licenses.
+func guessLicenseName(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	for _, pattern := range licensePatterns {
+		matched := true
+		for _, phrase := range pattern.phrases {
+			if !strings.Contains(strings.ToUpper(content), strings.ToUpper(phrase)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return pattern.name
+		}
+	}
+	return "unknown"
+}
+
+func isLicenseFileName(name string) bool {
+	name = strings.ToUpper(name)
+	return strings.HasPrefix(name, "LICENSE") || strings.HasPrefix(name, "LICENCE") || strings.HasPrefix(name, "COPYING")
 }
 
 func (m *Module) readLicense(root string) error {
-	// Find LICENSE*
-	entries, err := ioutil.ReadDir(filepath.Join(root, m.Path))
+	return m.readLicenseFS(DirFS(root), ".")
+}
+
+func (m *Module) readLicenseFS(fsys fs.FS, root string) error {
+	// Find LICENSE*, LICENCE* or COPYING*
+	entries, err := fs.ReadDir(fsys, fsPath(root, m.Path))
 	if err != nil {
 		return err
 	}
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasPrefix(strings.ToUpper(entry.Name()), "LICENSE") {
-			licensePath := filepath.Join(root, m.Path, entry.Name())
-			licenseContent, err := ioutil.ReadFile(licensePath)
+		if !entry.IsDir() && isLicenseFileName(entry.Name()) {
+			licensePath := fsPath(root, m.Path, entry.Name())
+			licenseContent, err := fs.ReadFile(fsys, licensePath)
 			if err == nil {
 				m.LicenseContent = strings.TrimSpace(string(licenseContent))
 				return nil
@@ -39,8 +197,8 @@ func (m *Module) readLicense(root string) error {
 	}
 	for _, entry := range entries {
 		if !entry.IsDir() && strings.HasPrefix(strings.ToUpper(entry.Name()), "README") {
-			readmePath := filepath.Join(root, m.Path, entry.Name())
-			f, err := os.Open(readmePath)
+			readmePath := fsPath(root, m.Path, entry.Name())
+			f, err := fsys.Open(readmePath)
 			if err != nil {
 				continue
 			}
@@ -73,15 +231,29 @@ func (m *Module) readLicense(root string) error {
 }
 
 func UniqueModules(modules []Module) []Module {
-	used := make(map[string]bool)
+	used := make(map[string]int)
 	result := []Module{}
 	for _, module := range modules {
 		key := module.Lang + "----" + module.Path
-		if used[key] {
+		if index, ok := used[key]; ok {
+			// the package can be bundled as scripts, stylesheets and fonts
+			for _, assetType := range module.AssetTypes {
+				result[index].addAssetType(assetType)
+			}
+			// license banners of the bundle are used if the package is not installed
+			if result[index].LicenseContent == "" && module.LicenseContent != "" {
+				result[index].LicenseName = module.LicenseName
+				result[index].LicenseContent = module.LicenseContent
+				result[index].Author = module.Author
+				result[index].Authors = module.Authors
+			}
+			if result[index].Version == "" {
+				result[index].Version = module.Version
+			}
 			continue
 		}
+		used[key] = len(result)
 		result = append(result, module)
-		used[key] = true
 	}
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].Name < result[j].Name
@@ -89,6 +261,36 @@ func UniqueModules(modules []Module) []Module {
 	return result
 }
 
+// CopyrightHolders returns names of author and contributors.
+// If the module doesn't have them, it returns the repository owner.
+func (m Module) CopyrightHolders() []string {
+	var result []string
+	var owners []string
+	for _, author := range m.Authors {
+		switch author.Role {
+		case AuthorRoleAuthor, AuthorRoleContributor:
+			result = append(result, author.displayName())
+		case AuthorRoleOwner:
+			owners = append(owners, author.displayName())
+		}
+	}
+	if len(result) == 0 {
+		return owners
+	}
+	return result
+}
+
+// ProprietaryModules returns modules that are declared as UNLICENSED.
+func ProprietaryModules(modules []Module) []Module {
+	result := []Module{}
+	for _, module := range modules {
+		if module.Proprietary {
+			result = append(result, module)
+		}
+	}
+	return result
+}
+
 type GroupedModule struct {
 	Author string
 	License string
@@ -120,14 +322,35 @@ func GroupingModulesByLicense(modules []Module) []GroupedModule {
 
 var projectDataReaders = map[string]func(*Module, string) error{}
 
+// projectDataFSReaders are readers that read the project in fs.FS. root is the slash separated path in the file system.
+var projectDataFSReaders = map[string]func(*Module, fs.FS, string) error{}
+
 func RegisterProjectDataReader(language string, reader func(*Module, string) error) {
 	projectDataReaders[language] = reader
 }
 
+// RegisterProjectDataReaderFS registers the reader of the language that supports fs.FS.
+// It is used by ReadProjectData too if RegisterProjectDataReader is not called for the language.
+func RegisterProjectDataReaderFS(language string, reader func(*Module, fs.FS, string) error) {
+	projectDataFSReaders[language] = reader
+}
+
 func ReadProjectData(module *Module, root string) error {
 	reader, ok := projectDataReaders[module.Lang]
 	if !ok {
+		if fsReader, ok := projectDataFSReaders[module.Lang]; ok {
+			return fsReader(module, DirFS(root), ".")
+		}
 		return fmt.Errorf("lang %s is not supported", module.Lang)
 	}
 	return reader(module, root)
+}
+
+// ReadProjectDataFS reads the project data of the module in the file system. root is the project folder in fsys.
+func ReadProjectDataFS(module *Module, fsys fs.FS, root string) error {
+	reader, ok := projectDataFSReaders[module.Lang]
+	if !ok {
+		return fmt.Errorf("lang %s doesn't support fs.FS", module.Lang)
+	}
+	return reader(module, fsys, root)
 }
\ No newline at end of file
@@ -199,4 +199,81 @@ func TestGroupingModuleByLicense(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestModule_Checksums(t *testing.T) {
+	tests := []struct {
+		name   string
+		module Module
+		want   []Checksum
+	}{
+		{
+			name: "integrity",
+			module: Module{
+				Integrity: "sha1-Ys4HkA5rF9YIHJ3F1M3Hn2Fp7Oc=",
+				DirHash:   "h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60=",
+			},
+			want: []Checksum{
+				{Algorithm: "SHA1", Value: "62ce07900e6b17d6081c9dc5d4cdc79f6169ece7"},
+			},
+		},
+		{
+			name:   "dirhash only",
+			module: Module{DirHash: "h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60="},
+			want:   nil,
+		},
+		{
+			name:   "no hashes",
+			module: Module{},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.module.Checksums(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Checksums() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_guessLicenseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "MIT",
+			content: "MIT License\n\nPermission is hereby granted, free of\ncharge, to any person",
+			want:    "MIT",
+		},
+		{
+			name:    "Apache",
+			content: "                                 Apache License\n                           Version 2.0, January 2004",
+			want:    "Apache-2.0",
+		},
+		{
+			name:    "BSD 2 clause",
+			content: "Redistribution and use in source and binary forms, with or without modification, are permitted",
+			want:    "BSD-2-Clause",
+		},
+		{
+			name:    "LGPL",
+			content: "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007",
+			want:    "LGPL-3.0",
+		},
+		{
+			name:    "unknown",
+			content: "All rights reserved.",
+			want:    "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessLicenseName(tt.content); got != tt.want {
+				t.Errorf("guessLicenseName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
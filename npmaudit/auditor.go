@@ -0,0 +1,165 @@
+package npmaudit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Auditor runs the package manager's audit command and normalizes the result into AuditReport.
+type Auditor interface {
+	Name() string
+	Audit(ctx context.Context, root string) (*AuditReport, error)
+}
+
+const (
+	AuditorNpm       = "npm"
+	AuditorYarn      = "yarn"
+	AuditorYarnBerry = "yarn-berry"
+	AuditorPnpm      = "pnpm"
+)
+
+// AuditorNames is the list of supported package managers.
+var AuditorNames = []string{AuditorNpm, AuditorYarn, AuditorYarnBerry, AuditorPnpm}
+
+// NewAuditor returns the auditor of the package manager.
+func NewAuditor(name string) (Auditor, error) {
+	switch name {
+	case AuditorNpm:
+		return NpmAuditor{}, nil
+	case AuditorYarn:
+		return YarnAuditor{}, nil
+	case AuditorYarnBerry:
+		return YarnBerryAuditor{}, nil
+	case AuditorPnpm:
+		return PnpmAuditor{}, nil
+	}
+	return nil, fmt.Errorf("unknown auditor: %s", name)
+}
+
+// DetectAuditor selects the auditor by the lockfile in the root folder. npm is used if no lockfile is found.
+func DetectAuditor(root string) Auditor {
+	if exists(filepath.Join(root, "pnpm-lock.yaml")) {
+		return PnpmAuditor{}
+	}
+	if exists(filepath.Join(root, "yarn.lock")) {
+		if isYarnBerry(root) {
+			return YarnBerryAuditor{}
+		}
+		return YarnAuditor{}
+	}
+	return NpmAuditor{}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// isYarnBerry checks the project uses Yarn 2+. Yarn Berry's lockfile has "__metadata" section.
+func isYarnBerry(root string) bool {
+	if exists(filepath.Join(root, ".yarnrc.yml")) {
+		return true
+	}
+	f, err := os.Open(filepath.Join(root, "yarn.lock"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for i := 0; i < 10 && s.Scan(); i++ {
+		if bytes.HasPrefix(s.Bytes(), []byte("__metadata:")) {
+			return true
+		}
+	}
+	return false
+}
+
+// execAudit runs the audit command and parses its stdout.
+//
+// Package managers exit with non-zero status when they find vulnerabilities, so the exit status is
+// treated as an error only when the output is not a valid report. A command that is not installed or
+// crashed writes nothing to stdout, and it is reported as an error instead of an empty report.
+func execAudit(ctx context.Context, root string, parse func(io.Reader) (*AuditReport, error), name string, args ...string) (*AuditReport, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = root
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if runErr != nil && len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, commandError(name, runErr, stderr.Bytes())
+	}
+	result, err := parse(&stdout)
+	if err != nil || (runErr != nil && result.AuditReportVersion == 0) {
+		if runErr != nil {
+			return nil, commandError(name, runErr, stderr.Bytes())
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// commandError adds the command name and the first line of stderr to the error.
+func commandError(name string, err error, stderr []byte) error {
+	message := bytes.TrimSpace(stderr)
+	if i := bytes.IndexByte(message, '\n'); i != -1 {
+		message = bytes.TrimSpace(message[:i])
+	}
+	if len(message) == 0 {
+		return fmt.Errorf("%s audit: %w", name, err)
+	}
+	return fmt.Errorf("%s audit: %w: %s", name, err, message)
+}
+
+// NpmAuditor runs "npm audit --json" (npm 7 or later).
+type NpmAuditor struct{}
+
+func (NpmAuditor) Name() string {
+	return AuditorNpm
+}
+
+func (NpmAuditor) Audit(ctx context.Context, root string) (*AuditReport, error) {
+	return ExecNpmAudit(ctx, root)
+}
+
+// YarnAuditor runs "yarn audit --json" of Yarn classic (v1).
+type YarnAuditor struct{}
+
+func (YarnAuditor) Name() string {
+	return AuditorYarn
+}
+
+func (YarnAuditor) Audit(ctx context.Context, root string) (*AuditReport, error) {
+	return execAudit(ctx, root, parseYarnAuditReport, "yarn", "audit", "--json")
+}
+
+// YarnBerryAuditor runs "yarn npm audit --json" of Yarn Berry (v2 or later).
+type YarnBerryAuditor struct{}
+
+func (YarnBerryAuditor) Name() string {
+	return AuditorYarnBerry
+}
+
+func (YarnBerryAuditor) Audit(ctx context.Context, root string) (*AuditReport, error) {
+	return execAudit(ctx, root, parseYarnBerryAuditReport, "yarn", "npm", "audit", "--json", "--recursive")
+}
+
+// PnpmAuditor runs "pnpm audit --json".
+type PnpmAuditor struct{}
+
+func (PnpmAuditor) Name() string {
+	return AuditorPnpm
+}
+
+func (PnpmAuditor) Audit(ctx context.Context, root string) (*AuditReport, error) {
+	return execAudit(ctx, root, parseV1AuditReport, "pnpm", "audit", "--json")
+}
@@ -0,0 +1,311 @@
+package npmaudit
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAuditor(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "npm",
+			files: map[string]string{"package-lock.json": "{}"},
+			want:  AuditorNpm,
+		},
+		{
+			name:  "no lockfile",
+			files: map[string]string{},
+			want:  AuditorNpm,
+		},
+		{
+			name:  "yarn classic",
+			files: map[string]string{"yarn.lock": "# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.\n# yarn lockfile v1\n"},
+			want:  AuditorYarn,
+		},
+		{
+			name:  "yarn berry",
+			files: map[string]string{"yarn.lock": "# This file is generated by running \"yarn install\" inside your project.\n\n__metadata:\n  version: 6\n"},
+			want:  AuditorYarnBerry,
+		},
+		{
+			name:  "pnpm",
+			files: map[string]string{"pnpm-lock.yaml": "lockfileVersion: 5.4\n"},
+			want:  AuditorPnpm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "auditor")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			for name, content := range tt.files {
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+			assert.Equal(t, tt.want, DetectAuditor(dir).Name())
+		})
+	}
+}
+
+var lodashVia = Via{
+	Source:     1523,
+	Name:       "lodash",
+	Dependency: "lodash",
+	Title:      "Prototype Pollution",
+	URL:        "https://npmjs.com/advisories/1523",
+	Severity:   "low",
+	Range:      "<4.17.19",
+}
+
+var wantNormalizedReport = &AuditReport{
+	AuditReportVersion: 2,
+	Vulnerabilities: map[string]Vulnerability{
+		"lodash": {
+			Name:     "lodash",
+			Severity: "low",
+			Range:    "<4.17.19",
+			Cause:    []Via{lodashVia},
+		},
+	},
+	Metadata: Metadata{
+		Vulnerabilities: Vulnerabilities{
+			Low:   1,
+			Total: 1,
+		},
+	},
+}
+
+// wantPathsReport returns the normalized report that has the dependency paths of the findings.
+func wantPathsReport(lodashNodes ...string) *AuditReport {
+	return &AuditReport{
+		AuditReportVersion: 2,
+		Vulnerabilities: map[string]Vulnerability{
+			"@vue/cli-plugin-e2e-cypress": {
+				Name:         "@vue/cli-plugin-e2e-cypress",
+				Severity:     "low",
+				Nodes:        []string{"node_modules/@vue/cli-plugin-e2e-cypress"},
+				LogicalNodes: true,
+				CausedBy:     []string{"cypress"},
+			},
+			"cypress": {
+				Name:         "cypress",
+				Severity:     "low",
+				Nodes:        []string{"node_modules/@vue/cli-plugin-e2e-cypress/node_modules/cypress"},
+				LogicalNodes: true,
+				CausedBy:     []string{"lodash"},
+			},
+			"lodash": {
+				Name:         "lodash",
+				Severity:     "low",
+				Range:        "<4.17.19",
+				Nodes:        lodashNodes,
+				LogicalNodes: true,
+				Cause:        []Via{lodashVia},
+			},
+		},
+		Metadata: Metadata{
+			Vulnerabilities: Vulnerabilities{
+				Low:   3,
+				Total: 3,
+			},
+		},
+	}
+}
+
+const v1Advisories = `{
+	"actions": [],
+	"advisories": {
+		"1523": {
+			"findings": [
+				{
+					"version": "4.17.15",
+					"paths": [
+						"@vue/cli-plugin-e2e-cypress>cypress>lodash"
+					]
+				}
+			],
+			"id": 1523,
+			"title": "Prototype Pollution",
+			"module_name": "lodash",
+			"vulnerable_versions": "<4.17.19",
+			"patched_versions": ">=4.17.19",
+			"severity": "low",
+			"url": "https://npmjs.com/advisories/1523"
+		}
+	},
+	"metadata": {
+		"vulnerabilities": {
+			"info": 0,
+			"low": 1,
+			"moderate": 0,
+			"high": 0,
+			"critical": 0
+		},
+		"dependencies": 388,
+		"devDependencies": 2044
+	}
+}`
+
+func TestParseV1AuditReport(t *testing.T) {
+	got, err := parseV1AuditReport(strings.NewReader(v1Advisories))
+	assert.NoError(t, err)
+	assert.Equal(t, wantPathsReport("node_modules/@vue/cli-plugin-e2e-cypress/node_modules/cypress/node_modules/lodash"), got)
+}
+
+func TestParseYarnAuditReport(t *testing.T) {
+	src := `{"type":"auditAdvisory","data":{"resolution":{"id":1523,"path":"@vue/cli-plugin-e2e-cypress>cypress>lodash","dev":false,"optional":false,"bundled":false},"advisory":{"findings":[{"version":"4.17.15","paths":["@vue/cli-plugin-e2e-cypress>cypress>lodash"]}],"id":1523,"title":"Prototype Pollution","module_name":"lodash","vulnerable_versions":"<4.17.19","patched_versions":">=4.17.19","severity":"low","url":"https://npmjs.com/advisories/1523"}}}
+{"type":"auditAdvisory","data":{"resolution":{"id":1523,"path":"lodash","dev":false,"optional":false,"bundled":false},"advisory":{"findings":[{"version":"4.17.15","paths":["lodash"]}],"id":1523,"title":"Prototype Pollution","module_name":"lodash","vulnerable_versions":"<4.17.19","patched_versions":">=4.17.19","severity":"low","url":"https://npmjs.com/advisories/1523"}}}
+{"type":"auditSummary","data":{"vulnerabilities":{"info":0,"low":2,"moderate":0,"high":0,"critical":0},"dependencies":388,"devDependencies":2044,"optionalDependencies":0,"totalDependencies":2432}}
+`
+	got, err := parseYarnAuditReport(strings.NewReader(src))
+	assert.NoError(t, err)
+	assert.Equal(t, wantPathsReport("node_modules/@vue/cli-plugin-e2e-cypress/node_modules/cypress/node_modules/lodash", "node_modules/lodash"), got)
+}
+
+func TestParseYarnBerryAuditReport(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want *AuditReport
+	}{
+		{
+			name: "yarn 3",
+			src:  v1Advisories,
+			want: wantPathsReport("node_modules/@vue/cli-plugin-e2e-cypress/node_modules/cypress/node_modules/lodash"),
+		},
+		{
+			name: "yarn 4",
+			want: &AuditReport{
+				AuditReportVersion: 2,
+				Vulnerabilities: map[string]Vulnerability{
+					"@vue/cli-plugin-e2e-cypress": {
+						Name:         "@vue/cli-plugin-e2e-cypress",
+						Severity:     "low",
+						Nodes:        []string{"node_modules/@vue/cli-plugin-e2e-cypress"},
+						LogicalNodes: true,
+						CausedBy:     []string{"lodash"},
+					},
+					"lodash": {
+						Name:     "lodash",
+						Severity: "low",
+						Range:    "<4.17.19",
+						Nodes: []string{
+							"node_modules/@vue/cli-plugin-e2e-cypress/node_modules/lodash",
+							"node_modules/lodash",
+						},
+						LogicalNodes: true,
+						Cause:        []Via{lodashVia},
+					},
+				},
+				Metadata: Metadata{
+					Vulnerabilities: Vulnerabilities{
+						Low:   2,
+						Total: 2,
+					},
+				},
+			},
+			src: `{"value":"lodash","children":{"ID":1523,"Issue":"Prototype Pollution","URL":"https://npmjs.com/advisories/1523","Severity":"low","Vulnerable Versions":"<4.17.19","Tree Versions":["4.17.15"],"Dependents":["@vue/cli-plugin-e2e-cypress@npm:4.5.11","my-app@workspace:."]}}
+`,
+		},
+		{
+			name: "yarn 4 without dependents",
+			want: wantNormalizedReport,
+			src: `{"value":"lodash","children":{"ID":1523,"Issue":"Prototype Pollution","URL":"https://npmjs.com/advisories/1523","Severity":"low","Vulnerable Versions":"<4.17.19","Tree Versions":["4.17.15"],"Dependents":[]}}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYarnBerryAuditReport(strings.NewReader(tt.src))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAuditor_Audit_missingCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	t.Setenv("PATH", "/nonexistent")
+	for _, name := range AuditorNames {
+		t.Run(name, func(t *testing.T) {
+			auditor, err := NewAuditor(name)
+			assert.NoError(t, err)
+			report, err := auditor.Audit(context.Background(), dir)
+			assert.Error(t, err)
+			assert.Nil(t, report)
+		})
+	}
+}
+
+func TestYarnBerryDependencyPath(t *testing.T) {
+	assert.Equal(t, "cypress>lodash", yarnBerryDependencyPath("cypress@npm:6.8.0", "lodash"))
+	assert.Equal(t, "@vue/cli-plugin-e2e-cypress>lodash", yarnBerryDependencyPath("@vue/cli-plugin-e2e-cypress@npm:4.5.11", "lodash"))
+	assert.Equal(t, "lodash", yarnBerryDependencyPath("my-app@workspace:.", "lodash"))
+	assert.Equal(t, "cypress>lodash", yarnBerryDependencyPath("cypress", "lodash"))
+}
+
+func TestDependencyPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "yarn",
+			path: "@vue/cli-plugin-e2e-cypress>cypress>lodash",
+			want: []string{"@vue/cli-plugin-e2e-cypress", "cypress", "lodash"},
+		},
+		{
+			name: "pnpm",
+			path: ".>express>send>debug",
+			want: []string{"express", "send", "debug"},
+		},
+		{
+			name: "pnpm with spaces",
+			path: ". > debug",
+			want: []string{"debug"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dependencyPath(tt.path))
+		})
+	}
+}
+
+func TestParseYarnAuditReport_error(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(io.Reader) (*AuditReport, error)
+		src   string
+	}{
+		{
+			name:  "yarn classic",
+			parse: parseYarnAuditReport,
+			src:   `{"type":"error","data":"An unexpected error occurred: \"https://registry.yarnpkg.com/-/npm/v1/security/audits/quick: Request failed \\\"503 Service Unavailable\\\"\"."}` + "\n",
+		},
+		{
+			name:  "yarn berry",
+			parse: parseYarnBerryAuditReport,
+			src:   `{"type":"error","data":"The remote server failed to provide the requested resource"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse(strings.NewReader(tt.src))
+			assert.Error(t, err)
+		})
+	}
+}
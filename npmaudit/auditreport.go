@@ -4,8 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
-	"os/exec"
+	"io/ioutil"
 )
 
 type AuditReport struct {
@@ -15,15 +16,39 @@ type AuditReport struct {
 }
 
 type Vulnerability struct {
-	Name         string
-	Severity     string
-	Range        string
-	Nodes        []string
+	// Lang is Module.Lang of the package. It is empty for npm, yarn and pnpm reports that have only "js" packages.
+	Lang     string
+	Name     string
+	Severity string
+	Range    string
+	Nodes    []string
+	// LogicalNodes is true when Nodes are made from the dependency paths of yarn and pnpm reports.
+	// Package managers hoist packages to the parent node_modules, so the installed copy can be in the upper folder.
+	LogicalNodes bool
 	Cause        []Via
 	CausedBy     []string
 	FixAvailable *FixAvailable
 }
 
+// Key returns the key of the vulnerability in AuditReport.Vulnerabilities.
+func (v Vulnerability) Key() string {
+	return VulnerabilityKey(v.Lang, v.Name)
+}
+
+// JS returns true if the vulnerability is about npm package.
+func (v Vulnerability) JS() bool {
+	return v.Lang == "" || v.Lang == "js"
+}
+
+// VulnerabilityKey returns the key of AuditReport.Vulnerabilities. npm packages are keyed by the name like npm does,
+// and the packages of other languages have the language prefix like "go/golang.org/x/net" not to collide with them.
+func VulnerabilityKey(lang, name string) string {
+	if lang == "" || lang == "js" {
+		return name
+	}
+	return lang + "/" + name
+}
+
 type vulnerability struct {
 	Name         string          `json:"name"`
 	Severity     string          `json:"severity"`
@@ -33,7 +58,10 @@ type vulnerability struct {
 	FixAvailable json.RawMessage `json:"fixAvailable"`
 }
 
-var falseBytes = []byte("false")
+var (
+	falseBytes = []byte("false")
+	nullBytes  = []byte("null")
+)
 
 func (v *Vulnerability) UnmarshalJSON(data []byte) error {
 	var raw vulnerability
@@ -45,7 +73,8 @@ func (v *Vulnerability) UnmarshalJSON(data []byte) error {
 	v.Severity = raw.Severity
 	v.Range = raw.Range
 	v.Nodes = raw.Nodes
-	if !bytes.Equal(falseBytes, raw.FixAvailable) {
+	// fixAvailable is false, true (fixed by `npm audit fix`) or the upgrade
+	if len(raw.FixAvailable) > 0 && !bytes.Equal(falseBytes, raw.FixAvailable) && !bytes.Equal(nullBytes, raw.FixAvailable) {
 		var fix FixAvailable
 		json.Unmarshal(raw.FixAvailable, &fix)
 		v.FixAvailable = &fix
@@ -104,31 +133,43 @@ func parseAuditReport(r io.Reader) (*AuditReport, error) {
 	return &result, nil
 }
 
-func ExecNpmAudit(ctx context.Context, root string) (*AuditReport, error) {
-	cmd := exec.CommandContext(ctx, "npm", "audit", "--json")
-	cmd.Dir = root
-	r, err := cmd.StdoutPipe()
+// ReadAuditReport reads pre-generated audit report.
+// It accepts "npm audit --json" output of npm 7+ and npm 6, "pnpm audit --json", "yarn audit --json" and "yarn npm audit --json".
+func ReadAuditReport(r io.Reader) (*AuditReport, error) {
+	src, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	defer r.Close()
-	var result *AuditReport
-	var parseErr error
-	go func() {
-		result, parseErr = parseAuditReport(r)
-	}()
-	err = cmd.Start()
-	if err != nil {
-		return nil, err
+	var probe struct {
+		AuditReportVersion int             `json:"auditReportVersion"`
+		Advisories         json.RawMessage `json:"advisories"`
+		Type               string          `json:"type"`
 	}
-	err = cmd.Wait()
-	if err != nil {
-		return nil, err
+	if err := json.Unmarshal(src, &probe); err == nil {
+		switch {
+		case probe.AuditReportVersion != 0:
+			return parseAuditReport(bytes.NewReader(src))
+		case probe.Advisories != nil:
+			return parseV1AuditReport(bytes.NewReader(src))
+		}
+	}
+	// NDJSON
+	firstLine := src
+	if i := bytes.IndexByte(bytes.TrimSpace(src), '\n'); i != -1 {
+		firstLine = bytes.TrimSpace(src)[:i]
 	}
-	if parseErr != nil {
-		return nil, parseErr
+	if err := json.Unmarshal(firstLine, &probe); err != nil {
+		return nil, fmt.Errorf("unknown audit report format: %w", err)
 	}
-	return result, nil
+	if probe.Type != "" {
+		return parseYarnAuditReport(bytes.NewReader(src))
+	}
+	return parseYarnBerryAuditReport(bytes.NewReader(src))
+}
+
+// ExecNpmAudit runs "npm audit --json" in the root folder.
+func ExecNpmAudit(ctx context.Context, root string) (*AuditReport, error) {
+	return execAudit(ctx, root, parseAuditReport, "npm", "audit", "--json")
 }
 
 type Via struct {
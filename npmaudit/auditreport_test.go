@@ -1,6 +1,7 @@
 package npmaudit
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -292,3 +293,108 @@ func TestParseAuditReport(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnerability_UnmarshalJSON_fixAvailable(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want *FixAvailable
+	}{
+		{
+			name: "false",
+			src:  `{"name": "lodash", "fixAvailable": false}`,
+			want: nil,
+		},
+		{
+			name: "missing",
+			src:  `{"name": "lodash"}`,
+			want: nil,
+		},
+		{
+			name: "true",
+			src:  `{"name": "lodash", "fixAvailable": true}`,
+			want: &FixAvailable{},
+		},
+		{
+			name: "upgrade",
+			src:  `{"name": "lodash", "fixAvailable": {"name": "webpack", "version": "5.0.0", "isSemVerMajor": true}}`,
+			want: &FixAvailable{Name: "webpack", Version: "5.0.0", IsSemVerMajor: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Vulnerability
+			assert.NoError(t, json.Unmarshal([]byte(tt.src), &got))
+			assert.Equal(t, tt.want, got.FixAvailable)
+		})
+	}
+}
+
+func TestReadAuditReport(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "npm 7",
+			src: `{
+	"auditReportVersion": 2,
+	"vulnerabilities": {
+		"lodash": {
+			"name": "lodash",
+			"severity": "low",
+			"via": [
+				{
+					"source": 1523,
+					"name": "lodash",
+					"dependency": "lodash",
+					"title": "Prototype Pollution",
+					"url": "https://npmjs.com/advisories/1523",
+					"severity": "low",
+					"range": "<4.17.19"
+				}
+			],
+			"effects": [],
+			"range": "<4.17.19",
+			"nodes": [],
+			"fixAvailable": false
+		}
+	},
+	"metadata": {
+		"vulnerabilities": {
+			"info": 0,
+			"low": 1,
+			"moderate": 0,
+			"high": 0,
+			"critical": 0,
+			"total": 1
+		}
+	}
+}`,
+		},
+		{
+			name: "npm 6",
+			src:  v1Advisories,
+		},
+		{
+			name: "yarn classic",
+			src: `{"type":"auditAdvisory","data":{"advisory":{"id":1523,"title":"Prototype Pollution","module_name":"lodash","vulnerable_versions":"<4.17.19","severity":"low","url":"https://npmjs.com/advisories/1523"}}}
+{"type":"auditSummary","data":{"vulnerabilities":{"low":1}}}`,
+		},
+		{
+			name: "yarn 4",
+			src:  `{"value":"lodash","children":{"ID":1523,"Issue":"Prototype Pollution","URL":"https://npmjs.com/advisories/1523","Severity":"low","Vulnerable Versions":"<4.17.19"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadAuditReport(strings.NewReader(tt.src))
+			assert.NoError(t, err)
+			assert.Equal(t, "low", got.Vulnerabilities["lodash"].Severity)
+			assert.Equal(t, []Via{lodashVia}, got.Vulnerabilities["lodash"].Cause)
+			assert.Equal(t, len(got.Vulnerabilities), got.Metadata.Vulnerabilities.Total)
+		})
+	}
+	_, err := ReadAuditReport(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package npmaudit
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// v1AuditReport is npm 6's audit report format. pnpm and Yarn Berry 2, 3 use this format.
+type v1AuditReport struct {
+	Advisories map[string]v1Advisory `json:"advisories"`
+	Metadata   struct {
+		Vulnerabilities Vulnerabilities `json:"vulnerabilities"`
+	} `json:"metadata"`
+}
+
+type v1Advisory struct {
+	ID                 int         `json:"id"`
+	Title              string      `json:"title"`
+	ModuleName         string      `json:"module_name"`
+	Severity           string      `json:"severity"`
+	URL                string      `json:"url"`
+	VulnerableVersions string      `json:"vulnerable_versions"`
+	PatchedVersions    string      `json:"patched_versions"`
+	GitHubAdvisoryID   string      `json:"github_advisory_id"`
+	Findings           []v1Finding `json:"findings"`
+}
+
+type v1Finding struct {
+	Version string   `json:"version"`
+	Paths   []string `json:"paths"`
+}
+
+var severityLevels = map[string]int{"info": 1, "low": 2, "moderate": 3, "high": 4, "critical": 5}
+
+func (a v1Advisory) via() Via {
+	url := a.URL
+	if url == "" && a.GitHubAdvisoryID != "" {
+		url = "https://github.com/advisories/" + a.GitHubAdvisoryID
+	}
+	return Via{
+		Source:     a.ID,
+		Name:       a.ModuleName,
+		Dependency: a.ModuleName,
+		Title:      a.Title,
+		URL:        url,
+		Severity:   a.Severity,
+		Range:      a.VulnerableVersions,
+	}
+}
+
+// addAdvisory merges the advisory into the vulnerability of the package.
+func (r *AuditReport) addAdvisory(via Via) {
+	v, ok := r.Vulnerabilities[via.Name]
+	if !ok {
+		v = Vulnerability{
+			Name:     via.Name,
+			Severity: via.Severity,
+			Range:    via.Range,
+		}
+	} else if !strings.Contains(v.Range, via.Range) {
+		v.Range = v.Range + " || " + via.Range
+	}
+	if severityLevels[via.Severity] > severityLevels[v.Severity] {
+		v.Severity = via.Severity
+	}
+	v.Cause = append(v.Cause, via)
+	r.Vulnerabilities[via.Name] = v
+}
+
+// addPaths adds the dependency paths of the advisory like "a>b>vulnerable" (yarn) or ".>a>b>vulnerable" (pnpm).
+// They are converted into the install paths of npm's nested layout, and the packages on the paths are added as
+// the vulnerabilities caused by the next package like npm 7's report.
+func (r *AuditReport) addPaths(via Via, paths []string) {
+	for _, p := range paths {
+		names := dependencyPath(p)
+		if len(names) == 0 || names[len(names)-1] != via.Name {
+			continue
+		}
+		for i, name := range names {
+			v, ok := r.Vulnerabilities[name]
+			if !ok {
+				v = Vulnerability{Name: name, Severity: via.Severity}
+			}
+			if severityLevels[via.Severity] > severityLevels[v.Severity] {
+				v.Severity = via.Severity
+			}
+			v.LogicalNodes = true
+			v.Nodes = appendUnique(v.Nodes, "node_modules/"+strings.Join(names[:i+1], "/node_modules/"))
+			if i+1 < len(names) {
+				v.CausedBy = appendUnique(v.CausedBy, names[i+1])
+			}
+			r.Vulnerabilities[name] = v
+		}
+	}
+}
+
+// dependencyPath splits the path of the finding. pnpm's root project "." is removed.
+func dependencyPath(path string) []string {
+	var result []string
+	for i, name := range strings.Split(path, ">") {
+		name = strings.TrimSpace(name)
+		if name == "" || (i == 0 && name == ".") {
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func (a v1Advisory) paths() []string {
+	var result []string
+	for _, f := range a.Findings {
+		result = append(result, f.Paths...)
+	}
+	return result
+}
+
+// countVulnerabilities fills Metadata.Vulnerabilities by the severity of the vulnerable packages.
+func (r *AuditReport) countVulnerabilities() {
+	var counts Vulnerabilities
+	for _, v := range r.Vulnerabilities {
+		switch v.Severity {
+		case "critical":
+			counts.Critical++
+		case "high":
+			counts.High++
+		case "moderate":
+			counts.Moderate++
+		case "low":
+			counts.Low++
+		case "info":
+			counts.Info++
+		}
+		counts.Total++
+	}
+	r.Metadata.Vulnerabilities = counts
+}
+
+func newNormalizedReport() *AuditReport {
+	return &AuditReport{
+		// normalized into version 2 format
+		AuditReportVersion: 2,
+		Vulnerabilities:    map[string]Vulnerability{},
+	}
+}
+
+func v1ToAuditReport(src v1AuditReport) *AuditReport {
+	result := newNormalizedReport()
+	ids := make([]string, 0, len(src.Advisories))
+	for id := range src.Advisories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		advisory := src.Advisories[id]
+		via := advisory.via()
+		result.addAdvisory(via)
+		result.addPaths(via, advisory.paths())
+	}
+	result.countVulnerabilities()
+	return result
+}
+
+// parseV1AuditReport parses npm 6 style report ("advisories" map).
+func parseV1AuditReport(r io.Reader) (*AuditReport, error) {
+	var src v1AuditReport
+	err := json.NewDecoder(r).Decode(&src)
+	if err != nil {
+		return nil, err
+	}
+	return v1ToAuditReport(src), nil
+}
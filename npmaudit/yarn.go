@@ -0,0 +1,181 @@
+package npmaudit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+type yarnAuditLine struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// yarnError is the error of the audit command. Yarn writes {"type":"error","data":"message"} lines to stdout
+// when the registry request fails or the lockfile is missing.
+type yarnError struct {
+	messages []string
+}
+
+func (e *yarnError) add(line []byte) bool {
+	var l yarnAuditLine
+	if json.Unmarshal(line, &l) != nil || l.Type != "error" {
+		return false
+	}
+	var message string
+	if json.Unmarshal(l.Data, &message) != nil {
+		message = string(l.Data)
+	}
+	e.messages = append(e.messages, message)
+	return true
+}
+
+// err returns the error if the output had only error lines.
+func (e *yarnError) err(reported bool) error {
+	if reported || len(e.messages) == 0 {
+		return nil
+	}
+	return errors.New("yarn audit: " + strings.Join(e.messages, "; "))
+}
+
+type yarnAuditAdvisory struct {
+	Resolution struct {
+		Path string `json:"path"`
+	} `json:"resolution"`
+	Advisory v1Advisory `json:"advisory"`
+}
+
+// parseYarnAuditReport parses NDJSON output of Yarn classic's "yarn audit --json".
+func parseYarnAuditReport(r io.Reader) (*AuditReport, error) {
+	result := newNormalizedReport()
+	used := map[int]bool{}
+	var yarnErr yarnError
+	reported := false
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, bufio.MaxScanTokenSize), 100*bufio.MaxScanTokenSize)
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var l yarnAuditLine
+		if err := json.Unmarshal(line, &l); err != nil {
+			return nil, err
+		}
+		switch l.Type {
+		case "auditSummary":
+			reported = true
+		case "error":
+			yarnErr.add(line)
+		}
+		if l.Type != "auditAdvisory" {
+			continue
+		}
+		reported = true
+		var a yarnAuditAdvisory
+		if err := json.Unmarshal(l.Data, &a); err != nil {
+			return nil, err
+		}
+		via := a.Advisory.via()
+		// same advisory is reported for each dependency path
+		if !used[a.Advisory.ID] {
+			used[a.Advisory.ID] = true
+			result.addAdvisory(via)
+		}
+		result.addPaths(via, append(a.Advisory.paths(), a.Resolution.Path))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if err := yarnErr.err(reported); err != nil {
+		return nil, err
+	}
+	result.countVulnerabilities()
+	return result, nil
+}
+
+// yarnBerryAuditLine is the line of Yarn 4's "yarn npm audit --json" output.
+type yarnBerryAuditLine struct {
+	Value    string `json:"value"`
+	Children struct {
+		ID                 json.Number `json:"ID"`
+		Issue              string      `json:"Issue"`
+		URL                string      `json:"URL"`
+		Severity           string      `json:"Severity"`
+		VulnerableVersions string      `json:"Vulnerable Versions"`
+		// Dependents are the packages that depend on the vulnerable package ("<name>@<reference>")
+		Dependents []string `json:"Dependents"`
+	} `json:"children"`
+}
+
+// parseYarnBerryAuditReport parses "yarn npm audit --json" output.
+// Yarn 2, 3 write npm 6 style report and Yarn 4 writes NDJSON.
+func parseYarnBerryAuditReport(r io.Reader) (*AuditReport, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var v1 v1AuditReport
+	if err := json.Unmarshal(src, &v1); err == nil && v1.Advisories != nil {
+		return v1ToAuditReport(v1), nil
+	}
+	result := newNormalizedReport()
+	var yarnErr yarnError
+	reported := false
+	for _, line := range bytes.Split(src, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || yarnErr.add(line) {
+			continue
+		}
+		var l yarnBerryAuditLine
+		if err := json.Unmarshal(line, &l); err != nil {
+			return nil, err
+		}
+		if l.Value == "" {
+			continue
+		}
+		reported = true
+		id, _ := l.Children.ID.Int64()
+		via := Via{
+			Source:     int(id),
+			Name:       l.Value,
+			Dependency: l.Value,
+			Title:      l.Children.Issue,
+			URL:        l.Children.URL,
+			Severity:   strings.ToLower(l.Children.Severity),
+			Range:      l.Children.VulnerableVersions,
+		}
+		result.addAdvisory(via)
+		var paths []string
+		for _, dependent := range l.Children.Dependents {
+			if dependent == "" {
+				continue
+			}
+			paths = append(paths, yarnBerryDependencyPath(dependent, l.Value))
+		}
+		result.addPaths(via, paths)
+	}
+	if err := yarnErr.err(reported); err != nil {
+		return nil, err
+	}
+	result.countVulnerabilities()
+	return result, nil
+}
+
+// yarnBerryDependencyPath converts the dependent ("cypress@npm:6.8.0") of the vulnerable package into the dependency
+// path ("cypress>lodash"). Workspaces ("my-app@workspace:.") depend on the package directly.
+func yarnBerryDependencyPath(dependent, name string) string {
+	// the first "@" is the scope of the name
+	i := strings.Index(dependent[1:], "@") + 1
+	if i == 0 {
+		return dependent + ">" + name
+	}
+	if strings.HasPrefix(dependent[i+1:], "workspace:") {
+		return name
+	}
+	return dependent[:i] + ">" + name
+}
@@ -0,0 +1,226 @@
+package linkedpackage
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type dotnetDeps struct {
+	Targets   map[string]map[string]dotnetTarget `json:"targets"`
+	Libraries map[string]dotnetLibrary           `json:"libraries"`
+}
+
+type dotnetTarget struct {
+	Runtime        map[string]json.RawMessage `json:"runtime"`
+	Native         map[string]json.RawMessage `json:"native"`
+	RuntimeTargets map[string]json.RawMessage `json:"runtimeTargets"`
+	Resources      map[string]json.RawMessage `json:"resources"`
+}
+
+func (t dotnetTarget) hasAssets() bool {
+	return len(t.Runtime) > 0 || len(t.Native) > 0 || len(t.RuntimeTargets) > 0 || len(t.Resources) > 0
+}
+
+type dotnetLibrary struct {
+	Type   string `json:"type"`
+	SHA512 string `json:"sha512"`
+	Path   string `json:"path"`
+}
+
+// ParseDotnetDeps reads NuGet packages from the application's *.deps.json.
+// Packages that don't have runtime assets in any target (e.g. meta packages and analyzers) are skipped.
+// Module.Path is the folder in the NuGet cache ("/<lower case id>/<lower case version>").
+func ParseDotnetDeps(path string) ([]Module, error) {
+	return ParseDotnetDepsFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseDotnetDepsFS reads NuGet packages from the application's *.deps.json in the file system.
+func ParseDotnetDepsFS(fsys fs.FS, name string) ([]Module, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var deps dotnetDeps
+	if err := json.NewDecoder(f).Decode(&deps); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var result []Module
+	for key, library := range deps.Libraries {
+		if library.Type != "package" {
+			continue
+		}
+		name, version, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		if !dotnetHasAssets(deps, key) {
+			continue
+		}
+		module := Module{
+			Lang:      "nuget",
+			Name:      name,
+			Version:   version,
+			Integrity: library.SHA512,
+			Path:      "/" + library.Path,
+		}
+		if library.Path == "" {
+			module.Path = "/" + strings.ToLower(name) + "/" + strings.ToLower(version)
+		}
+		result = append(result, module)
+	}
+	return UniqueModules(result), nil
+}
+
+// dotnetHasAssets returns true if the library has assets that are loaded at runtime.
+// It returns true if targets don't have the library not to miss packages.
+func dotnetHasAssets(deps dotnetDeps, key string) bool {
+	found := false
+	for _, target := range deps.Targets {
+		t, ok := target[key]
+		if !ok {
+			continue
+		}
+		if t.hasAssets() {
+			return true
+		}
+		found = true
+	}
+	return !found
+}
+
+type nuspec struct {
+	Metadata struct {
+		ID          string `xml:"id"`
+		Version     string `xml:"version"`
+		Authors     string `xml:"authors"`
+		Owners      string `xml:"owners"`
+		Description string `xml:"description"`
+		ProjectURL  string `xml:"projectUrl"`
+		LicenseURL  string `xml:"licenseUrl"`
+		License     struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"license"`
+		Repository struct {
+			URL string `xml:"url,attr"`
+		} `xml:"repository"`
+	} `xml:"metadata"`
+}
+
+// nugetPackages returns the global packages folder like `dotnet nuget locals global-packages --list`.
+func nugetPackages() string {
+	if packages := os.Getenv("NUGET_PACKAGES"); packages != "" {
+		return packages
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".nuget", "packages")
+}
+
+// projectNuGetReader reads .nuspec and license of the package. root is the NuGet global packages folder;
+// ~/.nuget/packages (or $NUGET_PACKAGES) is used if root is empty.
+func projectNuGetReader(module *Module, root string) error {
+	if root == "" {
+		root = nugetPackages()
+	}
+	return projectNuGetReaderFS(module, DirFS(root), ".")
+}
+
+// projectNuGetReaderFS reads .nuspec and license of the package. root is the NuGet global packages folder in the file system.
+func projectNuGetReaderFS(module *Module, fsys fs.FS, root string) error {
+	dir := fsPath(root, module.Path)
+	content, err := fs.ReadFile(fsys, path.Join(dir, strings.ToLower(module.Name)+".nuspec"))
+	if err != nil {
+		return err
+	}
+	var spec nuspec
+	if err := xml.Unmarshal(content, &spec); err != nil {
+		return fmt.Errorf("%s: %w", module.Name, err)
+	}
+	metadata := spec.Metadata
+
+	for _, author := range splitPeople(metadata.Authors) {
+		module.Authors = append(module.Authors, Author{Name: author, Role: AuthorRoleAuthor})
+	}
+	for _, owner := range splitPeople(metadata.Owners) {
+		module.Authors = append(module.Authors, Author{Name: owner, Role: AuthorRoleMaintainer})
+	}
+	if len(module.Authors) > 0 {
+		module.Author = module.Authors[0].displayName()
+	} else {
+		module.Author = module.Name + " authors"
+	}
+	module.Description = strings.Join(strings.Fields(metadata.Description), " ")
+	module.Homepage = strings.TrimSpace(metadata.ProjectURL)
+	module.Repository = normalizeJSRepositoryURL(strings.TrimSpace(metadata.Repository.URL))
+
+	license := strings.TrimSpace(metadata.License.Value)
+	switch {
+	case metadata.License.Type == "expression" && license != "":
+		module.LicenseName = license
+	case metadata.License.Type == "file" && license != "":
+		module.LicenseName = licenseRefID(module.Name, license)
+		// the license file must be in the package
+		file, ok := packageFilePath(license)
+		if !ok {
+			break
+		}
+		module.LicenseFile = file
+		content, err := fs.ReadFile(fsys, fsPath(dir, file))
+		if err == nil {
+			module.LicenseContent = strings.TrimSpace(string(content))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
+	case metadata.LicenseURL != "":
+		module.LicenseName = nugetLicenseURLName(strings.TrimSpace(metadata.LicenseURL))
+	}
+	if module.LicenseContent == "" {
+		if err := module.readLicenseFS(fsys, root); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
+	}
+	if module.LicenseName == "" {
+		if module.LicenseContent != "" {
+			module.LicenseName = guessLicenseName(module.LicenseContent)
+		} else {
+			module.LicenseName = "no license"
+		}
+	}
+	return nil
+}
+
+// nugetLicenseURLName converts deprecated <licenseUrl> into the license name.
+// https://licenses.nuget.org/<expression> is what NuGet generates for <license type="expression">.
+func nugetLicenseURLName(licenseURL string) string {
+	if u, err := url.Parse(licenseURL); err == nil && u.Host == "licenses.nuget.org" {
+		if expression := strings.Trim(u.Path, "/"); expression != "" {
+			return expression
+		}
+	}
+	return javaLicenseName("", licenseURL)
+}
+
+func nugetPURL(module Module) PURL {
+	return PURL{
+		Type:    "nuget",
+		Name:    module.Name,
+		Version: module.Version,
+	}
+}
+
+func init() {
+	RegisterProjectDataReader("nuget", projectNuGetReader)
+	RegisterProjectDataReaderFS("nuget", projectNuGetReaderFS)
+	RegisterPURLBuilder("nuget", nugetPURL)
+}
@@ -0,0 +1,114 @@
+package linkedpackage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDotnetDeps(t *testing.T) {
+	got, err := ParseDotnetDeps(filepath.Join("testdata", "dotnet", "DesktopTool.deps.json"))
+	assert.NoError(t, err)
+	// the project itself and the analyzer that doesn't have runtime assets are skipped
+	assert.Equal(t, []Module{
+		{Lang: "nuget", Name: "Acme.Widgets", Version: "2.0.0", Path: "/acme.widgets/2.0.0", Integrity: "sha512-AAAA"},
+		{Lang: "nuget", Name: "Legacy.Lib", Version: "1.0.0", Path: "/legacy.lib/1.0.0", Integrity: "sha512-BBBB"},
+		{Lang: "nuget", Name: "Newtonsoft.Json", Version: "13.0.1", Path: "/newtonsoft.json/13.0.1", Integrity: "sha512-ppPFpBcvxdsfUonNcvITKqLl3bqxWbDCZIzDWHzjpdAHRFfZe0Dw9HmA0+za13IdyrgJwpkDTDA9fHaxOrt20A=="},
+	}, got)
+}
+
+func Test_projectNuGetReader(t *testing.T) {
+	root := filepath.Join("testdata", "nuget-packages")
+	tests := []struct {
+		name   string
+		module Module
+		want   Module
+	}{
+		{
+			name:   "license expression",
+			module: Module{Lang: "nuget", Name: "Newtonsoft.Json", Version: "13.0.1", Path: "/newtonsoft.json/13.0.1"},
+			want: Module{
+				Lang:           "nuget",
+				Name:           "Newtonsoft.Json",
+				Version:        "13.0.1",
+				Path:           "/newtonsoft.json/13.0.1",
+				Author:         "James Newton-King",
+				Authors:        []Author{{Name: "James Newton-King", Role: AuthorRoleAuthor}},
+				LicenseName:    "MIT",
+				LicenseContent: "The MIT License (MIT)\n\nCopyright (c) 2007 James Newton-King",
+				Description:    "Json.NET is a popular high-performance JSON framework for .NET",
+				Homepage:       "https://www.newtonsoft.com/json",
+				Repository:     "https://github.com/JamesNK/Newtonsoft.Json",
+			},
+		},
+		{
+			name:   "license file",
+			module: Module{Lang: "nuget", Name: "Acme.Widgets", Version: "2.0.0", Path: "/acme.widgets/2.0.0"},
+			want: Module{
+				Lang:    "nuget",
+				Name:    "Acme.Widgets",
+				Version: "2.0.0",
+				Path:    "/acme.widgets/2.0.0",
+				Author:  "Acme Corporation",
+				Authors: []Author{
+					{Name: "Acme Corporation", Role: AuthorRoleAuthor},
+					{Name: "acme", Role: AuthorRoleMaintainer},
+				},
+				LicenseName:    "LicenseRef-Acme.Widgets-EULA.txt",
+				LicenseFile:    "docs/EULA.txt",
+				LicenseContent: "Acme Widgets End User License Agreement",
+				Description:    "Widgets for desktop tools",
+			},
+		},
+		{
+			name:   "deprecated license URL",
+			module: Module{Lang: "nuget", Name: "Legacy.Lib", Version: "1.0.0", Path: "/legacy.lib/1.0.0"},
+			want: Module{
+				Lang:    "nuget",
+				Name:    "Legacy.Lib",
+				Version: "1.0.0",
+				Path:    "/legacy.lib/1.0.0",
+				Author:  "Jane Doe",
+				Authors: []Author{
+					{Name: "Jane Doe", Role: AuthorRoleAuthor},
+					{Name: "John Doe", Role: AuthorRoleAuthor},
+				},
+				LicenseName: "Apache-2.0",
+				Description: "Native interop library",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, root))
+			assert.Equal(t, tt.want, module)
+
+			module = tt.module
+			assert.NoError(t, ReadProjectDataFS(&module, DirFS("testdata"), "nuget-packages"))
+			assert.Equal(t, tt.want, module)
+		})
+	}
+
+	// the license file out of the package is not read
+	fsys := NewMemFS(map[string][]byte{
+		"evil/1.0.0/evil.nuspec": []byte(`<package><metadata><id>Evil</id><version>1.0.0</version><license type="file">../../secret.txt</license></metadata></package>`),
+		"secret.txt":             []byte("secret"),
+	})
+	evil := Module{Lang: "nuget", Name: "Evil", Version: "1.0.0", Path: "/evil/1.0.0"}
+	assert.NoError(t, ReadProjectDataFS(&evil, fsys, "."))
+	assert.Empty(t, evil.LicenseFile)
+	assert.NotContains(t, evil.LicenseContent, "secret")
+}
+
+func Test_nugetLicenseURLName(t *testing.T) {
+	assert.Equal(t, "MIT OR Apache-2.0", nugetLicenseURLName("https://licenses.nuget.org/MIT%20OR%20Apache-2.0"))
+	assert.Equal(t, "Apache-2.0", nugetLicenseURLName("http://www.apache.org/licenses/LICENSE-2.0"))
+	assert.Equal(t, "https://example.com/eula", nugetLicenseURLName("https://example.com/eula"))
+}
+
+func Test_nugetPURL(t *testing.T) {
+	m := Module{Lang: "nuget", Name: "Newtonsoft.Json", Version: "13.0.1"}
+	assert.Equal(t, "pkg:nuget/Newtonsoft.Json@13.0.1", m.PURL())
+}
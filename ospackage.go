@@ -0,0 +1,335 @@
+package linkedpackage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadOSRelease returns "<ID>-<VERSION_ID>" of /etc/os-release (or /usr/lib/os-release) under root.
+// VERSION_ID is omitted if it doesn't exist (e.g. Debian sid).
+func ReadOSRelease(root string) (string, error) {
+	return ReadOSReleaseFS(DirFS(root), ".")
+}
+
+// ReadOSReleaseFS returns "<ID>-<VERSION_ID>" of /etc/os-release (or /usr/lib/os-release) under root in the file system.
+func ReadOSReleaseFS(fsys fs.FS, root string) (string, error) {
+	content, err := fs.ReadFile(fsys, fsPath(root, "etc", "os-release"))
+	if errors.Is(err, fs.ErrNotExist) {
+		content, err = fs.ReadFile(fsys, fsPath(root, "usr", "lib", "os-release"))
+	}
+	if err != nil {
+		return "", err
+	}
+	values := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	if values["ID"] == "" {
+		return "", fmt.Errorf("%s: ID is not found", fsPath(root, "etc", "os-release"))
+	}
+	if values["VERSION_ID"] == "" {
+		return values["ID"], nil
+	}
+	return values["ID"] + "-" + values["VERSION_ID"], nil
+}
+
+// parseDebControl reads paragraphs of Debian control file format. Continuation lines of multi-line fields are
+// joined with "\n".
+func parseDebControl(r io.Reader) ([]map[string]string, error) {
+	var result []map[string]string
+	paragraph := map[string]string{}
+	lastKey := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(paragraph) > 0 {
+				result = append(result, paragraph)
+				paragraph = map[string]string{}
+			}
+			lastKey = ""
+		case line[0] == ' ' || line[0] == '\t':
+			if lastKey != "" {
+				paragraph[lastKey] += "\n" + strings.TrimSpace(line)
+			}
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			lastKey = key
+			paragraph[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(paragraph) > 0 {
+		result = append(result, paragraph)
+	}
+	return result, scanner.Err()
+}
+
+// ParseDpkgStatus reads installed packages from dpkg's database (/var/lib/dpkg/status or a file in
+// /var/lib/dpkg/status.d of distroless images). distro is the result of ReadOSRelease.
+// Module.Path is the documentation folder ("/usr/share/doc/<package>") that has the copyright file.
+func ParseDpkgStatus(path, distro string) ([]Module, error) {
+	return ParseDpkgStatusFS(DirFS(filepath.Dir(path)), filepath.Base(path), distro)
+}
+
+// ParseDpkgStatusFS reads installed packages from dpkg's database in the file system.
+func ParseDpkgStatusFS(fsys fs.FS, name, distro string) ([]Module, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	paragraphs, err := parseDebControl(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var result []Module
+	for _, paragraph := range paragraphs {
+		name := paragraph["Package"]
+		if name == "" {
+			continue
+		}
+		// status.d files don't have Status field
+		if status, ok := paragraph["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		module := Module{
+			Lang:     "deb",
+			Name:     name,
+			Path:     "/usr/share/doc/" + name,
+			Version:  paragraph["Version"],
+			Homepage: paragraph["Homepage"],
+			Distro:   distro,
+		}
+		module.Description, _, _ = strings.Cut(paragraph["Description"], "\n")
+		if maintainer := paragraph["Maintainer"]; maintainer != "" {
+			author := parseAuthorString(maintainer)
+			author.Role = AuthorRoleMaintainer
+			module.Authors = []Author{author}
+		}
+		result = append(result, module)
+	}
+	return result, nil
+}
+
+// ParseApkInstalled reads installed packages from apk's database (/lib/apk/db/installed).
+// distro is the result of ReadOSRelease.
+// Module.Path is the folder that has license files ("/usr/share/licenses/<package>").
+func ParseApkInstalled(path, distro string) ([]Module, error) {
+	return ParseApkInstalledFS(DirFS(filepath.Dir(path)), filepath.Base(path), distro)
+}
+
+// ParseApkInstalledFS reads installed packages from apk's database in the file system.
+func ParseApkInstalledFS(fsys fs.FS, name, distro string) ([]Module, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var result []Module
+	var module *Module
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if module != nil && module.Name != "" {
+				result = append(result, *module)
+			}
+			module = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if module == nil {
+			module = &Module{Lang: "apk", Distro: distro}
+		}
+		switch key {
+		case "P":
+			module.Name = value
+			module.Path = "/usr/share/licenses/" + value
+		case "V":
+			module.Version = value
+		case "T":
+			module.Description = value
+		case "U":
+			module.Homepage = value
+		case "L":
+			module.LicenseName = normalizeApkLicense(value)
+		case "m":
+			author := parseAuthorString(value)
+			author.Role = AuthorRoleMaintainer
+			module.Authors = []Author{author}
+		}
+	}
+	if module != nil && module.Name != "" {
+		result = append(result, *module)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return result, nil
+}
+
+// normalizeApkLicense converts space separated license list of old APKBUILDs into SPDX expression.
+func normalizeApkLicense(license string) string {
+	fields := strings.Fields(license)
+	for _, field := range fields {
+		switch strings.ToUpper(field) {
+		case "AND", "OR", "WITH":
+			return strings.Join(fields, " ")
+		}
+	}
+	return strings.Join(fields, " AND ")
+}
+
+// debLicenseNames maps short license names of Debian's machine-readable copyright files into SPDX identifiers.
+var debLicenseNames = map[string]string{
+	"Expat":         "MIT",
+	"GPL-1+":        "GPL-1.0-or-later",
+	"GPL-2":         "GPL-2.0-only",
+	"GPL-2+":        "GPL-2.0-or-later",
+	"GPL-3":         "GPL-3.0-only",
+	"GPL-3+":        "GPL-3.0-or-later",
+	"LGPL-2":        "LGPL-2.0-only",
+	"LGPL-2+":       "LGPL-2.0-or-later",
+	"LGPL-2.1":      "LGPL-2.1-only",
+	"LGPL-2.1+":     "LGPL-2.1-or-later",
+	"LGPL-3":        "LGPL-3.0-only",
+	"LGPL-3+":       "LGPL-3.0-or-later",
+	"BSD-2-clause":  "BSD-2-Clause",
+	"BSD-3-clause":  "BSD-3-Clause",
+	"BSD-4-clause":  "BSD-4-Clause",
+	"Artistic":      "Artistic-1.0-Perl",
+	"Apache-2":      "Apache-2.0",
+	"MPL-2":         "MPL-2.0",
+	"public-domain": "LicenseRef-public-domain",
+	"permissive":    "LicenseRef-permissive",
+	"GFDL-1.2+":     "GFDL-1.2-or-later",
+	"GFDL-1.3+":     "GFDL-1.3-or-later",
+	"PSF-2":         "PSF-2.0",
+}
+
+// debCopyrightLicenses returns licenses of the machine-readable copyright file (DEP-5) in the order of appearance.
+// It returns nil for free-form copyright files.
+func debCopyrightLicenses(content string) []string {
+	if !strings.HasPrefix(content, "Format:") {
+		return nil
+	}
+	paragraphs, err := parseDebControl(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	var result []string
+	found := map[string]bool{}
+	for _, paragraph := range paragraphs {
+		license, _, _ := strings.Cut(paragraph["License"], "\n")
+		if license == "" {
+			continue
+		}
+		fields := strings.Fields(license)
+		for i, field := range fields {
+			switch strings.ToLower(field) {
+			case "and", "or", "with":
+				fields[i] = strings.ToUpper(field)
+			default:
+				if name, ok := debLicenseNames[strings.TrimSuffix(field, ",")]; ok {
+					fields[i] = name
+				}
+			}
+		}
+		license = strings.Join(fields, " ")
+		if !found[license] {
+			found[license] = true
+			result = append(result, license)
+		}
+	}
+	return result
+}
+
+func osPackageAuthor(module *Module) {
+	if len(module.Authors) > 0 {
+		module.Author = module.Authors[0].displayName()
+	} else {
+		module.Author = module.Name + " authors"
+	}
+}
+
+// projectDebReader reads /usr/share/doc/<package>/copyright. root is the root folder of the file system
+// (e.g. container image).
+func projectDebReader(module *Module, fsys fs.FS, root string) error {
+	osPackageAuthor(module)
+	content, err := fs.ReadFile(fsys, fsPath(root, module.Path, "copyright"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		module.LicenseName = "no license"
+		return nil
+	}
+	module.LicenseContent = strings.TrimSpace(string(content))
+	if licenses := debCopyrightLicenses(module.LicenseContent); len(licenses) > 0 {
+		module.LicenseName = strings.Join(licenses, " AND ")
+	} else {
+		module.LicenseName = guessLicenseName(module.LicenseContent)
+	}
+	return nil
+}
+
+// projectApkReader reads license files in /usr/share/licenses/<package>. root is the root folder of the file system.
+// Most packages don't install license files, so the license name of the apk database is used as is.
+func projectApkReader(module *Module, fsys fs.FS, root string) error {
+	osPackageAuthor(module)
+	// license files are optional
+	_ = module.readLicenseFS(fsys, root)
+	if module.LicenseName == "" {
+		if module.LicenseContent != "" {
+			module.LicenseName = guessLicenseName(module.LicenseContent)
+		} else {
+			module.LicenseName = "no license"
+		}
+	}
+	return nil
+}
+
+func osPackagePURL(module Module, defaultNamespace string) PURL {
+	result := PURL{
+		Type:      module.Lang,
+		Namespace: defaultNamespace,
+		Name:      module.Name,
+		Version:   module.Version,
+	}
+	if module.Distro != "" {
+		result.Namespace, _, _ = strings.Cut(module.Distro, "-")
+		result.Qualifiers = map[string]string{"distro": module.Distro}
+	}
+	return result
+}
+
+func debPURL(module Module) PURL {
+	return osPackagePURL(module, "debian")
+}
+
+func apkPURL(module Module) PURL {
+	return osPackagePURL(module, "alpine")
+}
+
+func init() {
+	RegisterProjectDataReaderFS("deb", projectDebReader)
+	RegisterPURLBuilder("deb", debPURL)
+	RegisterProjectDataReaderFS("apk", projectApkReader)
+	RegisterPURLBuilder("apk", apkPURL)
+}
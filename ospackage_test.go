@@ -0,0 +1,132 @@
+package linkedpackage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOSRelease(t *testing.T) {
+	distro, err := ReadOSRelease(filepath.Join("testdata", "ospackage", "debian"))
+	assert.NoError(t, err)
+	assert.Equal(t, "debian-12", distro)
+	distro, err = ReadOSRelease(filepath.Join("testdata", "ospackage", "alpine"))
+	assert.NoError(t, err)
+	assert.Equal(t, "alpine-3.19.1", distro)
+	_, err = ReadOSRelease(filepath.Join("testdata", "ospackage"))
+	assert.Error(t, err)
+}
+
+func TestParseDpkgStatus(t *testing.T) {
+	root := filepath.Join("testdata", "ospackage", "debian")
+	modules, err := ParseDpkgStatus(filepath.Join(root, "var", "lib", "dpkg", "status"), "debian-12")
+	assert.NoError(t, err)
+	// oldpkg is removed
+	assert.Equal(t, []Module{
+		{
+			Lang:        "deb",
+			Name:        "zlib1g",
+			Path:        "/usr/share/doc/zlib1g",
+			Version:     "1:1.2.13.dfsg-1",
+			Description: "compression library - runtime",
+			Homepage:    "http://zlib.net/",
+			Authors:     []Author{{Name: "Mark Brown", Email: "broonie@debian.org", Role: AuthorRoleMaintainer}},
+			Distro:      "debian-12",
+		},
+		{
+			Lang:        "deb",
+			Name:        "libfoo",
+			Path:        "/usr/share/doc/libfoo",
+			Version:     "2.0-1",
+			Description: "foo library",
+			Authors:     []Author{{Name: "Foo Developers", Email: "foo@example.com", Role: AuthorRoleMaintainer}},
+			Distro:      "debian-12",
+		},
+	}, modules)
+
+	tests := []struct {
+		name        string
+		module      Module
+		licenseName string
+		author      string
+	}{
+		{
+			name:        "machine-readable copyright",
+			module:      modules[0],
+			licenseName: "Zlib AND GPL-2.0-or-later OR MIT",
+			author:      "Mark Brown <broonie@debian.org>",
+		},
+		{
+			name:        "free-form copyright",
+			module:      modules[1],
+			licenseName: "MIT",
+			author:      "Foo Developers <foo@example.com>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, root))
+			assert.Equal(t, tt.licenseName, module.LicenseName)
+			assert.Equal(t, tt.author, module.Author)
+			assert.Contains(t, module.LicenseContent, "Copyright")
+		})
+	}
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	root := filepath.Join("testdata", "ospackage", "alpine")
+	modules, err := ParseApkInstalled(filepath.Join(root, "lib", "apk", "db", "installed"), "alpine-3.19.1")
+	assert.NoError(t, err)
+	if assert.Len(t, modules, 3) {
+		assert.Equal(t, Module{
+			Lang:        "apk",
+			Name:        "musl",
+			Path:        "/usr/share/licenses/musl",
+			Version:     "1.2.4_git20230717-r4",
+			Description: "the musl c library (libc) implementation",
+			Homepage:    "https://musl.libc.org/",
+			LicenseName: "MIT",
+			Authors:     []Author{{Name: "Timo Teräs", Email: "timo.teras@iki.fi", Role: AuthorRoleMaintainer}},
+			Distro:      "alpine-3.19.1",
+		}, modules[0])
+		assert.Equal(t, "busybox", modules[1].Name)
+		// space separated licenses of old packages
+		assert.Equal(t, "MPL-2.0 AND MIT", modules[2].LicenseName)
+	}
+
+	musl := modules[0]
+	assert.NoError(t, ReadProjectData(&musl, root))
+	assert.Equal(t, "MIT", musl.LicenseName)
+	assert.Contains(t, musl.LicenseContent, "standard MIT license")
+	busybox := modules[1]
+	assert.NoError(t, ReadProjectData(&busybox, root))
+	assert.Equal(t, "GPL-2.0-only", busybox.LicenseName)
+	assert.Equal(t, "", busybox.LicenseContent)
+}
+
+func Test_osPackagePURL(t *testing.T) {
+	tests := []struct {
+		module Module
+		want   string
+	}{
+		{
+			module: Module{Lang: "deb", Name: "zlib1g", Version: "1:1.2.13.dfsg-1", Distro: "debian-12"},
+			want:   "pkg:deb/debian/zlib1g@1%3A1.2.13.dfsg-1?distro=debian-12",
+		},
+		{
+			module: Module{Lang: "deb", Name: "libc6", Version: "2.35-0ubuntu3", Distro: "ubuntu-22.04"},
+			want:   "pkg:deb/ubuntu/libc6@2.35-0ubuntu3?distro=ubuntu-22.04",
+		},
+		{
+			module: Module{Lang: "apk", Name: "musl", Version: "1.2.4-r4"},
+			want:   "pkg:apk/alpine/musl@1.2.4-r4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.module.PURL())
+		})
+	}
+}
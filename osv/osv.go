@@ -0,0 +1,396 @@
+// Package osv reads vulnerability database in Open Source Vulnerability format (https://ossf.github.io/osv-schema/)
+// and audits modules without network access.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/future-architect/linkedpackage/semver"
+)
+
+type Entry struct {
+	ID               string           `json:"id"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Aliases          []string         `json:"aliases"`
+	Withdrawn        string           `json:"withdrawn"`
+	Affected         []Affected       `json:"affected"`
+	References       []Reference      `json:"references"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Affected struct {
+	Package          Package          `json:"package"`
+	Ranges           []Range          `json:"ranges"`
+	Versions         []string         `json:"versions"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// ecosystems maps Module.Lang to OSV's ecosystem name.
+var ecosystems = map[string]string{
+	"js":     "npm",
+	"go":     "Go",
+	"rust":   "crates.io",
+	"java":   "Maven",
+	"python": "PyPI",
+	"nuget":  "NuGet",
+}
+
+// Database is the set of OSV entries indexed by ecosystem and package name.
+type Database struct {
+	entries map[string][]*Entry
+}
+
+func key(ecosystem, name string) string {
+	return ecosystem + "/" + name
+}
+
+// Load reads OSV JSON files from the folder or the zip file (e.g. https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip).
+func Load(path string) (*Database, error) {
+	db := &Database{
+		entries: map[string][]*Entry{},
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+				return nil
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return db.add(f, p)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !strings.HasSuffix(zf.Name, ".json") {
+			continue
+		}
+		f, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		err = db.add(f, zf.Name)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+func (db *Database) add(r io.Reader, name string) error {
+	var entry Entry
+	if err := json.NewDecoder(r).Decode(&entry); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if entry.Withdrawn != "" {
+		return nil
+	}
+	used := map[string]bool{}
+	for _, affected := range entry.Affected {
+		k := key(affected.Package.Ecosystem, affected.Package.Name)
+		if !used[k] {
+			used[k] = true
+			db.entries[k] = append(db.entries[k], &entry)
+		}
+	}
+	return nil
+}
+
+// Affects returns true if the version of the package is affected. Versions are compared by the ordering of
+// the ecosystem. It also returns true if the range can't be evaluated not to hide vulnerabilities.
+func (a Affected) Affects(version string) bool {
+	for _, v := range a.Versions {
+		if v == version {
+			return true
+		}
+	}
+	compare, ok := comparators[a.Package.Ecosystem]
+	if !ok {
+		// only the listed versions are known
+		return false
+	}
+	for _, r := range a.Ranges {
+		if r.Type == "SEMVER" {
+			// SEMVER ranges are semver in all ecosystems
+			compare = semver.Compare
+		} else if r.Type != "ECOSYSTEM" {
+			continue
+		}
+		affected, err := r.contains(compare, version)
+		if err != nil || affected {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Event) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+func (r Range) contains(compare compareFunc, v string) (bool, error) {
+	type comparedEvent struct {
+		event Event
+		// c is the result of comparing v with the event's version
+		c int
+	}
+	var events []comparedEvent
+	for _, e := range r.Events {
+		if e.Limit != "" {
+			continue
+		}
+		c, err := compare(v, e.version())
+		if err != nil {
+			return false, err
+		}
+		events = append(events, comparedEvent{event: e, c: c})
+	}
+	// events are sorted by version. an event with a smaller version compares v greater
+	sort.SliceStable(events, func(i, j int) bool {
+		ci, _ := compare(events[i].event.version(), events[j].event.version())
+		return ci < 0
+	})
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.event.Introduced != "":
+			if e.c >= 0 {
+				affected = true
+			}
+		case e.event.Fixed != "":
+			if e.c >= 0 {
+				affected = false
+			}
+		case e.event.LastAffected != "":
+			if e.c > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected, nil
+}
+
+// String returns range in npm's range syntax like ">=1.0.0 <1.2.3".
+func (r Range) String() string {
+	var result []string
+	var current string
+	for _, e := range r.Events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" {
+				current = ""
+			} else {
+				current = ">=" + e.Introduced
+			}
+		case e.Fixed != "":
+			result = append(result, strings.TrimSpace(current+" <"+e.Fixed))
+			current = ""
+		case e.LastAffected != "":
+			result = append(result, strings.TrimSpace(current+" <="+e.LastAffected))
+			current = ""
+		}
+	}
+	if current != "" {
+		result = append(result, current)
+	} else if len(result) == 0 && len(r.Events) > 0 {
+		result = append(result, "*")
+	}
+	return strings.Join(result, " || ")
+}
+
+func (a Affected) rangeString() string {
+	var result []string
+	for _, r := range a.Ranges {
+		if r.Type == "SEMVER" || r.Type == "ECOSYSTEM" {
+			result = append(result, r.String())
+		}
+	}
+	if len(result) == 0 {
+		return strings.Join(a.Versions, " || ")
+	}
+	return strings.Join(result, " || ")
+}
+
+// severity returns npm audit's severity name.
+func (e *Entry) severity(a Affected) string {
+	s := a.DatabaseSpecific.Severity
+	if s == "" {
+		s = e.DatabaseSpecific.Severity
+	}
+	switch strings.ToLower(s) {
+	case "critical":
+		return "critical"
+	case "high":
+		return "high"
+	case "moderate", "medium":
+		return "moderate"
+	case "low":
+		return "low"
+	}
+	// severity is unknown. treat it as moderate not to ignore it
+	return "moderate"
+}
+
+// URL returns the advisory URL. GitHub's URL is used for GHSA entries so that the advisory ID can be extracted from it.
+func (e *Entry) URL() string {
+	if strings.HasPrefix(e.ID, "GHSA-") {
+		return "https://github.com/advisories/" + e.ID
+	}
+	for _, r := range e.References {
+		if r.Type == "ADVISORY" {
+			return r.URL
+		}
+	}
+	return "https://osv.dev/vulnerability/" + e.ID
+}
+
+// Audit matches modules with the database and returns the result in npm audit's model.
+func (db *Database) Audit(modules []linkedpackage.Module) *npmaudit.AuditReport {
+	report := &npmaudit.AuditReport{
+		AuditReportVersion: 2,
+		Vulnerabilities:    map[string]npmaudit.Vulnerability{},
+	}
+	for _, m := range modules {
+		ecosystem, ok := ecosystems[m.Lang]
+		if !ok || m.Version == "" {
+			continue
+		}
+		for _, entry := range db.entries[key(ecosystem, m.Name)] {
+			for _, affected := range entry.Affected {
+				if affected.Package.Ecosystem != ecosystem || affected.Package.Name != m.Name || !affected.Affects(m.Version) {
+					continue
+				}
+				addFinding(report, m, entry, affected)
+			}
+		}
+	}
+	for _, v := range report.Vulnerabilities {
+		switch v.Severity {
+		case "critical":
+			report.Metadata.Vulnerabilities.Critical++
+		case "high":
+			report.Metadata.Vulnerabilities.High++
+		case "moderate":
+			report.Metadata.Vulnerabilities.Moderate++
+		case "low":
+			report.Metadata.Vulnerabilities.Low++
+		}
+		report.Metadata.Vulnerabilities.Total++
+	}
+	return report
+}
+
+var severityLevels = map[string]int{"info": 1, "low": 2, "moderate": 3, "high": 4, "critical": 5}
+
+func addFinding(report *npmaudit.AuditReport, m linkedpackage.Module, entry *Entry, affected Affected) {
+	severity := entry.severity(affected)
+	via := npmaudit.Via{
+		Name:       m.Name,
+		Dependency: m.Name,
+		Title:      entry.Summary,
+		URL:        entry.URL(),
+		Severity:   severity,
+		Range:      affected.rangeString(),
+	}
+	if via.Title == "" {
+		via.Title = entry.ID
+	}
+	// packages of different ecosystems can have the same name (e.g. "requests" of npm and PyPI)
+	k := npmaudit.VulnerabilityKey(m.Lang, m.Name)
+	v, ok := report.Vulnerabilities[k]
+	if !ok {
+		v = npmaudit.Vulnerability{
+			Lang:     m.Lang,
+			Name:     m.Name,
+			Severity: severity,
+			Range:    via.Range,
+		}
+	}
+	node := strings.TrimPrefix(m.Path, "/")
+	if !contains(v.Nodes, node) {
+		v.Nodes = append(v.Nodes, node)
+	}
+	for _, c := range v.Cause {
+		if c.URL == via.URL {
+			report.Vulnerabilities[k] = v
+			return
+		}
+	}
+	v.Cause = append(v.Cause, via)
+	if severityLevels[severity] > severityLevels[v.Severity] {
+		v.Severity = severity
+	}
+	report.Vulnerabilities[k] = v
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
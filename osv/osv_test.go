@@ -0,0 +1,260 @@
+package osv
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/future-architect/linkedpackage"
+	"github.com/future-architect/linkedpackage/npmaudit"
+	"github.com/future-architect/linkedpackage/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_contains(t *testing.T) {
+	r := Range{
+		Type: "SEMVER",
+		Events: []Event{
+			{Introduced: "0"},
+			{Fixed: "1.0.2"},
+			{Introduced: "2.0.0"},
+			{LastAffected: "2.2.1"},
+		},
+	}
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "0.5.0", want: true},
+		{version: "1.0.2", want: false},
+		{version: "1.5.0", want: false},
+		{version: "2.0.0", want: true},
+		{version: "2.2.1", want: true},
+		{version: "2.2.2", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := r.contains(semver.Compare, tt.version)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, "<1.0.2 || >=2.0.0 <=2.2.1", r.String())
+}
+
+func TestAffected_Affects(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem string
+		events    []Event
+		version   string
+		want      bool
+	}{
+		{
+			name:      "PyPI short version",
+			ecosystem: "PyPI",
+			events:    []Event{{Introduced: "2.3.0"}, {Fixed: "2.31.0"}},
+			version:   "2.4",
+			want:      true,
+		},
+		{
+			name:      "PyPI post release",
+			ecosystem: "PyPI",
+			events:    []Event{{Introduced: "0"}, {Fixed: "2.31.0"}},
+			version:   "2.31.0.post1",
+			want:      false,
+		},
+		{
+			name:      "PyPI release candidate",
+			ecosystem: "PyPI",
+			events:    []Event{{Introduced: "0"}, {Fixed: "2.31.0"}},
+			version:   "2.31.0rc1",
+			want:      true,
+		},
+		{
+			name:      "Maven Final",
+			ecosystem: "Maven",
+			events:    []Event{{Introduced: "0"}, {Fixed: "5.3.0.Final"}},
+			version:   "5.2.10.Final",
+			want:      true,
+		},
+		{
+			name:      "Maven fixed Final",
+			ecosystem: "Maven",
+			events:    []Event{{Introduced: "0"}, {Fixed: "5.3.0"}},
+			version:   "5.3.0.Final",
+			want:      false,
+		},
+		{
+			name:      "NuGet four numbers",
+			ecosystem: "NuGet",
+			events:    []Event{{Introduced: "0"}, {Fixed: "4.3.0.1"}},
+			version:   "4.3.0",
+			want:      true,
+		},
+		{
+			name:      "Go module version",
+			ecosystem: "Go",
+			events:    []Event{{Introduced: "0"}, {Fixed: "0.17.0"}},
+			version:   "v0.10.0",
+			want:      true,
+		},
+		{
+			name:      "invalid version is treated as affected",
+			ecosystem: "PyPI",
+			events:    []Event{{Introduced: "0"}, {Fixed: "2.31.0"}},
+			version:   "unknown",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Affected{
+				Package: Package{Ecosystem: tt.ecosystem, Name: "test"},
+				Ranges:  []Range{{Type: "ECOSYSTEM", Events: tt.events}},
+			}
+			assert.Equal(t, tt.want, a.Affects(tt.version))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		compare compareFunc
+		a       string
+		b       string
+		want    int
+	}{
+		{name: "PyPI dev < alpha", compare: comparePyPI, a: "1.0.dev1", b: "1.0a1", want: -1},
+		{name: "PyPI rc < release", compare: comparePyPI, a: "1.0rc2", b: "1.0", want: -1},
+		{name: "PyPI trailing zeros", compare: comparePyPI, a: "2.0", b: "2.0.0", want: 0},
+		{name: "PyPI post dev < post", compare: comparePyPI, a: "1.0.post1.dev1", b: "1.0.post1", want: -1},
+		{name: "PyPI epoch", compare: comparePyPI, a: "1!1.0", b: "2.0", want: 1},
+		{name: "Maven alpha < release", compare: compareMaven, a: "1.0-alpha1", b: "1.0", want: -1},
+		{name: "Maven Final == release", compare: compareMaven, a: "1.0.0.Final", b: "1.0", want: 0},
+		{name: "Maven sp > release", compare: compareMaven, a: "1.0-sp1", b: "1.0", want: 1},
+		{name: "Maven snapshot < release", compare: compareMaven, a: "2.1-SNAPSHOT", b: "2.1", want: -1},
+		{name: "Maven number > qualifier", compare: compareMaven, a: "1.0.1", b: "1.0-sp", want: 1},
+		{name: "NuGet prerelease", compare: compareNuGet, a: "4.0.0-Beta", b: "4.0.0", want: -1},
+		{name: "NuGet fourth number", compare: compareNuGet, a: "4.0.0.0", b: "4.0.0", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.compare(tt.a, tt.b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+var testModules = []linkedpackage.Module{
+	{Lang: "js", Name: "lodash", Path: "/node_modules/lodash", Version: "4.17.15"},
+	{Lang: "js", Name: "json5", Path: "/node_modules/json5", Version: "2.2.3"},
+	{Lang: "js", Name: "json5", Path: "/node_modules/babel/node_modules/json5", Version: "1.0.1"},
+	{Lang: "js", Name: "left-pad", Path: "/node_modules/left-pad", Version: "1.3.0"},
+}
+
+var wantReport = &npmaudit.AuditReport{
+	AuditReportVersion: 2,
+	Vulnerabilities: map[string]npmaudit.Vulnerability{
+		"lodash": {
+			Lang:     "js",
+			Name:     "lodash",
+			Severity: "high",
+			Range:    ">=3.7.0 <4.17.19",
+			Nodes:    []string{"node_modules/lodash"},
+			Cause: []npmaudit.Via{
+				{
+					Name:       "lodash",
+					Dependency: "lodash",
+					Title:      "Prototype Pollution in lodash",
+					URL:        "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+					Severity:   "high",
+					Range:      ">=3.7.0 <4.17.19",
+				},
+			},
+		},
+		"json5": {
+			Lang:     "js",
+			Name:     "json5",
+			Severity: "high",
+			Range:    "<1.0.2 || >=2.0.0 <2.2.2",
+			Nodes:    []string{"node_modules/babel/node_modules/json5"},
+			Cause: []npmaudit.Via{
+				{
+					Name:       "json5",
+					Dependency: "json5",
+					Title:      "Prototype Pollution in JSON5 via Parse Method",
+					URL:        "https://github.com/advisories/GHSA-9c47-m6qq-7p4h",
+					Severity:   "high",
+					Range:      "<1.0.2 || >=2.0.0 <2.2.2",
+				},
+			},
+		},
+	},
+	Metadata: npmaudit.Metadata{
+		Vulnerabilities: npmaudit.Vulnerabilities{
+			High:  2,
+			Total: 2,
+		},
+	},
+}
+
+func TestDatabase_Audit(t *testing.T) {
+	db, err := Load("../testdata/osv/npm")
+	assert.NoError(t, err)
+	assert.Equal(t, wantReport, db.Audit(testModules))
+}
+
+func TestDatabase_Audit_ecosystem(t *testing.T) {
+	db := &Database{entries: map[string][]*Entry{}}
+	for _, src := range []string{
+		`{"id": "GHSA-j8r2-6x86-q33q", "summary": "Unintended leak of Proxy-Authorization header in requests", "affected": [{"package": {"ecosystem": "PyPI", "name": "requests"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"fixed": "2.31.0"}]}]}], "database_specific": {"severity": "MODERATE"}}`,
+		`{"id": "GHSA-xxxx-npm-requests", "summary": "Vulnerability of npm requests", "affected": [{"package": {"ecosystem": "npm", "name": "requests"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "2.0.0"}]}]}], "database_specific": {"severity": "LOW"}}`,
+	} {
+		assert.NoError(t, db.add(strings.NewReader(src), "test.json"))
+	}
+	report := db.Audit([]linkedpackage.Module{
+		{Lang: "python", Name: "requests", Path: "/requests", Version: "2.28.0"},
+		{Lang: "js", Name: "requests", Path: "/node_modules/requests", Version: "1.0.0"},
+	})
+	if assert.Len(t, report.Vulnerabilities, 2) {
+		assert.Equal(t, "moderate", report.Vulnerabilities["python/requests"].Severity)
+		assert.Equal(t, "python", report.Vulnerabilities["python/requests"].Lang)
+		assert.Equal(t, []string{"requests"}, report.Vulnerabilities["python/requests"].Nodes)
+		assert.Equal(t, "low", report.Vulnerabilities["requests"].Severity)
+		assert.Equal(t, []string{"node_modules/requests"}, report.Vulnerabilities["requests"].Nodes)
+	}
+}
+
+func TestLoad_zip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osv")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	zipPath := filepath.Join(dir, "all.zip")
+	out, err := os.Create(zipPath)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(out)
+	files, err := filepath.Glob("../testdata/osv/npm/*.json")
+	assert.NoError(t, err)
+	for _, file := range files {
+		w, err := zw.Create(filepath.Base(file))
+		assert.NoError(t, err)
+		f, err := os.Open(file)
+		assert.NoError(t, err)
+		io.Copy(w, f)
+		f.Close()
+	}
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, out.Close())
+
+	db, err := Load(zipPath)
+	assert.NoError(t, err)
+	assert.Equal(t, wantReport, db.Audit(testModules))
+}
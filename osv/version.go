@@ -0,0 +1,322 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/future-architect/linkedpackage/semver"
+)
+
+// compareFunc compares two versions of the ecosystem. It returns -1, 0 or 1.
+type compareFunc func(a, b string) (int, error)
+
+// comparators are version orderings of OSV's "ECOSYSTEM" ranges.
+// Go and crates.io use semantic versions ("v" prefix of Go modules is accepted by semver.Parse).
+var comparators = map[string]compareFunc{
+	"npm":       semver.Compare,
+	"Go":        semver.Compare,
+	"crates.io": semver.Compare,
+	"PyPI":      comparePyPI,
+	"Maven":     compareMaven,
+	"NuGet":     compareNuGet,
+}
+
+func compareInts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseInts(fragments []string) ([]int, error) {
+	result := make([]int, len(fragments))
+	for i, fragment := range fragments {
+		n, err := strconv.Atoi(fragment)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid number %q", fragment)
+		}
+		result[i] = n
+	}
+	return result, nil
+}
+
+// pep440Pattern matches PEP 440 versions including the alternative spellings that pip accepts.
+var pep440Pattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+
+// pep440Version is the sort key of PEP 440 version. Local version labels are ignored.
+type pep440Version struct {
+	release []int
+	// key is epoch, pre-release phase and number, post-release and development release
+	key [5]int
+}
+
+func optionalNumber(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func parsePEP440(src string) (pep440Version, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(src)))
+	if match == nil {
+		return pep440Version{}, fmt.Errorf("pypi: invalid version %q", src)
+	}
+	release, err := parseInts(strings.Split(match[2], "."))
+	if err != nil {
+		return pep440Version{}, fmt.Errorf("pypi: invalid version %q", src)
+	}
+	var result pep440Version
+	result.release = release
+	result.key[0] = optionalNumber(match[1])
+	hasPost := match[5] != "" || match[6] != ""
+	hasDev := match[8] != ""
+	// pre-release: "1.0.dev0" < "1.0a1" < "1.0b1" < "1.0rc1" < "1.0"
+	switch match[3] {
+	case "":
+		if hasDev && !hasPost {
+			result.key[1] = -1
+		} else {
+			result.key[1] = 3
+		}
+	case "a", "alpha":
+		result.key[1] = 0
+	case "b", "beta":
+		result.key[1] = 1
+	default:
+		result.key[1] = 2
+	}
+	result.key[2] = optionalNumber(match[4])
+	// post-release: "1.0" < "1.0.post1"
+	result.key[3] = -1
+	if hasPost {
+		result.key[3] = optionalNumber(match[5] + match[7])
+	}
+	// development release: "1.0.post1.dev1" < "1.0.post1"
+	result.key[4] = math.MaxInt32
+	if hasDev {
+		result.key[4] = optionalNumber(match[9])
+	}
+	return result, nil
+}
+
+// comparePyPI compares versions by PEP 440 (e.g. "2.0" < "2.31.0rc1" < "2.31.0" < "2.31.0.post1").
+func comparePyPI(a, b string) (int, error) {
+	va, err := parsePEP440(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parsePEP440(b)
+	if err != nil {
+		return 0, err
+	}
+	if c := compareInts(va.key[:1], vb.key[:1]); c != 0 {
+		return c, nil
+	}
+	if c := compareInts(va.release, vb.release); c != 0 {
+		return c, nil
+	}
+	return compareInts(va.key[1:], vb.key[1:]), nil
+}
+
+// mavenQualifiers are the well-known qualifiers of Maven's ComparableVersion in order.
+// Empty qualifier is the release ("1.0" == "1.0.0.Final" == "1.0-ga").
+var mavenQualifiers = map[string]int{
+	"alpha":     0,
+	"beta":      1,
+	"milestone": 2,
+	"rc":        3,
+	"snapshot":  4,
+	"":          5,
+	"sp":        6,
+}
+
+var mavenAliases = map[string]string{
+	"a":       "alpha",
+	"b":       "beta",
+	"m":       "milestone",
+	"cr":      "rc",
+	"ga":      "",
+	"final":   "",
+	"release": "",
+}
+
+type mavenItem struct {
+	number    int
+	qualifier string
+	isNumber  bool
+}
+
+func (i mavenItem) isNull() bool {
+	if i.isNumber {
+		return i.number == 0
+	}
+	return i.qualifier == ""
+}
+
+func (i mavenItem) qualifierRank() int {
+	if rank, ok := mavenQualifiers[i.qualifier]; ok {
+		return rank
+	}
+	// unknown qualifiers come after the known ones
+	return len(mavenQualifiers)
+}
+
+// parseMaven splits the version into numbers and qualifiers. "." and "-" and the transitions between
+// digits and letters separate the items (e.g. "1.0.0.Final" and "1.2-beta2").
+func parseMaven(src string) ([]mavenItem, error) {
+	s := strings.ToLower(strings.TrimSpace(src))
+	if s == "" {
+		return nil, fmt.Errorf("maven: invalid version %q", src)
+	}
+	var result []mavenItem
+	add := func(token string, digit bool) error {
+		if digit {
+			n, err := strconv.Atoi(token)
+			if err != nil {
+				return fmt.Errorf("maven: invalid version %q", src)
+			}
+			result = append(result, mavenItem{number: n, isNumber: true})
+			return nil
+		}
+		if alias, ok := mavenAliases[token]; ok {
+			token = alias
+		}
+		result = append(result, mavenItem{qualifier: token})
+		return nil
+	}
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] != '.' && s[i] != '-' && (i == start || isDigit(s[i]) == isDigit(s[start])) {
+			continue
+		}
+		if i > start {
+			if err := add(s[start:i], isDigit(s[start])); err != nil {
+				return nil, err
+			}
+		}
+		start = i
+		if i < len(s) && (s[i] == '.' || s[i] == '-') {
+			start = i + 1
+		}
+	}
+	for len(result) > 0 && result[len(result)-1].isNull() {
+		result = result[:len(result)-1]
+	}
+	return result, nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func compareMavenItem(a, b mavenItem) int {
+	switch {
+	case a.isNumber && b.isNumber:
+		return compareInts([]int{a.number}, []int{b.number})
+	case a.isNumber:
+		// "1.0.1" > "1.0-sp"
+		return 1
+	case b.isNumber:
+		return -1
+	}
+	ra, rb := a.qualifierRank(), b.qualifierRank()
+	if ra != rb {
+		return compareInts([]int{ra}, []int{rb})
+	}
+	return strings.Compare(a.qualifier, b.qualifier)
+}
+
+// compareMaven compares versions like Maven's ComparableVersion (e.g. "1.0-alpha1" < "1.0" == "1.0.0.Final" < "1.0-sp1").
+func compareMaven(a, b string) (int, error) {
+	va, err := parseMaven(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseMaven(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		// missing items are null ("1.0" == "1.0.0")
+		ia, ib := mavenItem{isNumber: true}, mavenItem{isNumber: true}
+		if i < len(va) {
+			ia = va[i]
+		} else if !vb[i].isNumber {
+			ia = mavenItem{}
+		}
+		if i < len(vb) {
+			ib = vb[i]
+		} else if !va[i].isNumber {
+			ib = mavenItem{}
+		}
+		if c := compareMavenItem(ia, ib); c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+type nugetVersion struct {
+	release    []int
+	prerelease []string
+}
+
+// parseNuGet parses NuGet version that has up to four numbers ("1.2.3.4") and case-insensitive pre-release labels.
+func parseNuGet(src string) (nugetVersion, error) {
+	s := strings.ToLower(strings.TrimSpace(src))
+	if i := strings.Index(s, "+"); i != -1 {
+		s = s[:i]
+	}
+	var result nugetVersion
+	if i := strings.Index(s, "-"); i != -1 {
+		result.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	fragments := strings.Split(s, ".")
+	if len(fragments) > 4 {
+		return nugetVersion{}, fmt.Errorf("nuget: invalid version %q", src)
+	}
+	release, err := parseInts(fragments)
+	if err != nil {
+		return nugetVersion{}, fmt.Errorf("nuget: invalid version %q", src)
+	}
+	result.release = release
+	return result, nil
+}
+
+// compareNuGet compares NuGet versions (e.g. "4.0.0-beta" < "4.0.0" == "4.0.0.0" < "4.0.0.1").
+func compareNuGet(a, b string) (int, error) {
+	va, err := parseNuGet(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseNuGet(b)
+	if err != nil {
+		return 0, err
+	}
+	if c := compareInts(va.release, vb.release); c != 0 {
+		return c, nil
+	}
+	// a version without pre-release has higher precedence. labels are compared like semver
+	pa := semver.Version{Prerelease: va.prerelease}
+	pb := semver.Version{Prerelease: vb.prerelease}
+	return pa.Compare(pb), nil
+}
@@ -0,0 +1,99 @@
+package linkedpackage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PURL is the package URL that identifies the module across ecosystems.
+//
+// See https://github.com/package-url/purl-spec
+type PURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// String returns canonical form like "pkg:npm/%40scope/name@1.2.3".
+func (p PURL) String() string {
+	var b strings.Builder
+	b.WriteString("pkg:")
+	b.WriteString(strings.ToLower(p.Type))
+	b.WriteString("/")
+	if p.Namespace != "" {
+		for _, segment := range strings.Split(p.Namespace, "/") {
+			if segment == "" {
+				continue
+			}
+			b.WriteString(purlEscape(segment))
+			b.WriteString("/")
+		}
+	}
+	b.WriteString(purlEscape(p.Name))
+	if p.Version != "" {
+		b.WriteString("@")
+		b.WriteString(purlEscape(p.Version))
+	}
+	if len(p.Qualifiers) > 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for key, value := range p.Qualifiers {
+			if value != "" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if i == 0 {
+				b.WriteString("?")
+			} else {
+				b.WriteString("&")
+			}
+			b.WriteString(strings.ToLower(key))
+			b.WriteString("=")
+			b.WriteString(purlEscape(p.Qualifiers[key]))
+		}
+	}
+	if subpath := strings.Trim(p.Subpath, "/"); subpath != "" {
+		b.WriteString("#")
+		for i, segment := range strings.Split(subpath, "/") {
+			if i != 0 {
+				b.WriteString("/")
+			}
+			b.WriteString(purlEscape(segment))
+		}
+	}
+	return b.String()
+}
+
+func purlEscape(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '.' || c == '-' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+var purlBuilders = map[string]func(Module) PURL{}
+
+// RegisterPURLBuilder registers the function that creates package URL of the language's module.
+func RegisterPURLBuilder(language string, builder func(Module) PURL) {
+	purlBuilders[language] = builder
+}
+
+// PURL returns package URL of the module. It returns empty string if the language is not supported.
+func (m Module) PURL() string {
+	builder, ok := purlBuilders[m.Lang]
+	if !ok || m.Name == "" {
+		return ""
+	}
+	return builder(m).String()
+}
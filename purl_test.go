@@ -0,0 +1,77 @@
+package linkedpackage
+
+import (
+	"testing"
+)
+
+func TestPURL_String(t *testing.T) {
+	tests := []struct {
+		name string
+		purl PURL
+		want string
+	}{
+		{
+			name: "simple",
+			purl: PURL{Type: "npm", Name: "foo", Version: "1.2.3"},
+			want: "pkg:npm/foo@1.2.3",
+		},
+		{
+			name: "namespace is escaped",
+			purl: PURL{Type: "npm", Namespace: "@scope", Name: "name", Version: "1.2.3"},
+			want: "pkg:npm/%40scope/name@1.2.3",
+		},
+		{
+			name: "qualifiers are sorted",
+			purl: PURL{Type: "deb", Namespace: "debian", Name: "curl", Version: "7.50.3-1", Qualifiers: map[string]string{"distro": "jessie", "arch": "i386"}},
+			want: "pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+		},
+		{
+			name: "version and subpath",
+			purl: PURL{Type: "golang", Namespace: "github.com/gorilla", Name: "context", Version: "v1.0.0+incompatible", Subpath: "/api/"},
+			want: "pkg:golang/github.com/gorilla/context@v1.0.0%2Bincompatible#api",
+		},
+		{
+			name: "without version",
+			purl: PURL{Type: "npm", Name: "foo"},
+			want: "pkg:npm/foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.purl.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_PURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		module Module
+		want   string
+	}{
+		{
+			name:   "npm",
+			module: Module{Lang: "js", Name: "foo", Version: "1.2.3"},
+			want:   "pkg:npm/foo@1.2.3",
+		},
+		{
+			name:   "npm scoped package",
+			module: Module{Lang: "js", Name: "@babel/runtime", Version: "7.13.10"},
+			want:   "pkg:npm/%40babel/runtime@7.13.10",
+		},
+		{
+			name:   "unsupported language",
+			module: Module{Lang: "unknown", Name: "foo", Version: "1.2.3"},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.module.PURL(); got != tt.want {
+				t.Errorf("PURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
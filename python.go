@@ -0,0 +1,654 @@
+package linkedpackage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ParsePythonSitePackages reads distributions installed in the site-packages folder (*.dist-info).
+// Module.Path is the dist-info folder ("/<name>-<version>.dist-info").
+func ParsePythonSitePackages(sitePackages string) ([]Module, error) {
+	return ParsePythonSitePackagesFS(DirFS(filepath.Dir(sitePackages)), filepath.Base(sitePackages))
+}
+
+// ParsePythonSitePackagesFS reads distributions installed in the site-packages folder in the file system.
+func ParsePythonSitePackagesFS(fsys fs.FS, sitePackages string) ([]Module, error) {
+	distInfos, err := fs.Glob(fsys, fsPath(sitePackages, "*.dist-info"))
+	if err != nil {
+		return nil, err
+	}
+	if len(distInfos) == 0 {
+		return nil, fmt.Errorf("%s: dist-info is not found", sitePackages)
+	}
+	var result []Module
+	for _, distInfo := range distInfos {
+		header, err := readPythonMetadata(fsys, path.Join(distInfo, "METADATA"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", distInfo, err.Error())
+			continue
+		}
+		result = append(result, Module{
+			Lang:    "python",
+			Name:    header.Get("Name"),
+			Version: header.Get("Version"),
+			Path:    "/" + path.Base(distInfo),
+		})
+	}
+	return result, nil
+}
+
+// readPythonMetadata reads headers of core metadata file (METADATA or PKG-INFO).
+func readPythonMetadata(fsys fs.FS, metadataPath string) (mail.Header, error) {
+	f, err := fsys.Open(metadataPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	msg, err := mail.ReadMessage(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", metadataPath, err)
+	}
+	return msg.Header, nil
+}
+
+// pythonTopLevelNames returns importable names of the distribution from top_level.txt or RECORD.
+func pythonTopLevelNames(fsys fs.FS, sitePackages, distInfo string) []string {
+	used := map[string]bool{}
+	var result []string
+	add := func(name string) {
+		if name != "" && !used[name] {
+			used[name] = true
+			result = append(result, name)
+		}
+	}
+	if content, err := fs.ReadFile(fsys, fsPath(sitePackages, distInfo, "top_level.txt")); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			add(strings.TrimSpace(line))
+		}
+		return result
+	}
+	for _, file := range pythonRecordFiles(fsys, sitePackages, distInfo) {
+		if strings.HasPrefix(file, "..") || strings.Contains(file, ".dist-info/") || strings.HasPrefix(file, "__pycache__/") {
+			continue
+		}
+		add(pythonImportName(strings.SplitN(file, "/", 2)[0]))
+	}
+	return result
+}
+
+// pythonImportName converts the top-level file name into the import name ("six.py" → "six",
+// "_cffi_backend.cpython-39-x86_64-linux-gnu.so" → "_cffi_backend").
+func pythonImportName(file string) string {
+	for _, ext := range []string{".py", ".pyc", ".so", ".pyd"} {
+		if strings.HasSuffix(file, ext) {
+			file = strings.TrimSuffix(file, ext)
+			if i := strings.Index(file, "."); i != -1 {
+				file = file[:i]
+			}
+			return file
+		}
+	}
+	if strings.Contains(file, ".") {
+		// other data files (e.g. *.pth)
+		return ""
+	}
+	return file
+}
+
+// pythonRecordFiles returns paths in RECORD file.
+func pythonRecordFiles(fsys fs.FS, sitePackages, distInfo string) []string {
+	f, err := fsys.Open(fsPath(sitePackages, distInfo, "RECORD"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	var result []string
+	for {
+		record, err := r.Read()
+		if err != nil {
+			break
+		}
+		if len(record) > 0 && record[0] != "" {
+			result = append(result, filepath.ToSlash(record[0]))
+		}
+	}
+	return result
+}
+
+// pythonClassifierLicenses maps trove classifiers to SPDX identifiers.
+var pythonClassifierLicenses = map[string]string{
+	"MIT License":                                             "MIT",
+	"MIT No Attribution License (MIT-0)":                      "MIT-0",
+	"Apache Software License":                                 "Apache-2.0",
+	"BSD License":                                             "BSD",
+	"ISC License (ISCL)":                                      "ISC",
+	"Mozilla Public License 2.0 (MPL 2.0)":                    "MPL-2.0",
+	"GNU Lesser General Public License v2 (LGPLv2)":           "LGPL-2.0",
+	"GNU Lesser General Public License v2 or later (LGPLv2+)": "LGPL-2.0-or-later",
+	"GNU Lesser General Public License v3 (LGPLv3)":           "LGPL-3.0",
+	"GNU Lesser General Public License v3 or later (LGPLv3+)": "LGPL-3.0-or-later",
+	"GNU General Public License v2 (GPLv2)":                   "GPL-2.0",
+	"GNU General Public License v2 or later (GPLv2+)":         "GPL-2.0-or-later",
+	"GNU General Public License v3 (GPLv3)":                   "GPL-3.0",
+	"GNU General Public License v3 or later (GPLv3+)":         "GPL-3.0-or-later",
+	"Python Software Foundation License":                      "PSF-2.0",
+	"The Unlicense (Unlicense)":                               "Unlicense",
+	"Eclipse Public License 2.0 (EPL-2.0)":                    "EPL-2.0",
+	"Historical Permission Notice and Disclaimer (HPND)":      "HPND",
+	"Zope Public License":                                     "ZPL-2.1",
+}
+
+// pythonLicenseName chooses the license from License-Expression, License and classifiers in this order.
+// License field that has the whole license text is ignored.
+func pythonLicenseName(header mail.Header) string {
+	if expression := strings.TrimSpace(header.Get("License-Expression")); expression != "" {
+		return expression
+	}
+	license := strings.TrimSpace(header.Get("License"))
+	if license != "" && !strings.EqualFold(license, "UNKNOWN") && !strings.Contains(license, "\n") && len(license) <= 100 {
+		return license
+	}
+	var licenses []string
+	for _, classifier := range header["Classifier"] {
+		fragments := strings.Split(classifier, "::")
+		if len(fragments) < 2 || strings.TrimSpace(fragments[0]) != "License" {
+			continue
+		}
+		name := strings.TrimSpace(fragments[len(fragments)-1])
+		if id, ok := pythonClassifierLicenses[name]; ok {
+			name = id
+		}
+		if name != "OSI Approved" {
+			licenses = append(licenses, name)
+		}
+	}
+	return strings.Join(licenses, ", ")
+}
+
+// parsePythonPeople parses Author-email like "Name <email>, other@example.com".
+func parsePythonPeople(names, emails, role string) []Author {
+	var result []Author
+	for _, email := range splitPeople(emails) {
+		author := parseAuthorString(email)
+		if author.Email == "" && strings.Contains(author.Name, "@") {
+			author.Email = author.Name
+			author.Name = ""
+		}
+		author.Role = role
+		result = append(result, author)
+	}
+	for i, name := range splitPeople(names) {
+		if i < len(result) {
+			if result[i].Name == "" {
+				result[i].Name = name
+			}
+			continue
+		}
+		result = append(result, Author{Name: name, Role: role})
+	}
+	return result
+}
+
+// splitPeople splits comma separated names. "UNKNOWN" that old packaging tools set is removed.
+func splitPeople(src string) []string {
+	var result []string
+	for _, person := range strings.Split(src, ",") {
+		person = strings.Trim(strings.TrimSpace(person), `"`)
+		if person != "" && !strings.EqualFold(person, "UNKNOWN") {
+			result = append(result, person)
+		}
+	}
+	return result
+}
+
+// projectPythonReader reads METADATA and license files of the distribution. root is the site-packages folder.
+func projectPythonReader(module *Module, fsys fs.FS, root string) error {
+	distInfo := strings.TrimPrefix(module.Path, "/")
+	header, err := readPythonMetadata(fsys, fsPath(root, distInfo, "METADATA"))
+	if err != nil {
+		return err
+	}
+	module.Authors = append(parsePythonPeople(header.Get("Author"), header.Get("Author-Email"), AuthorRoleAuthor),
+		parsePythonPeople(header.Get("Maintainer"), header.Get("Maintainer-Email"), AuthorRoleMaintainer)...)
+	module.Description = header.Get("Summary")
+	module.Homepage = header.Get("Home-Page")
+	for _, projectURL := range header["Project-Url"] {
+		label, u, ok := strings.Cut(projectURL, ",")
+		if !ok {
+			continue
+		}
+		label = strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(label))
+		u = strings.TrimSpace(u)
+		switch label {
+		case "homepage":
+			if module.Homepage == "" {
+				module.Homepage = u
+			}
+		case "source", "sourcecode", "repository", "code":
+			module.Repository = u
+		case "bugtracker", "issues", "issuetracker", "tracker", "bugs":
+			module.Bugs = u
+		case "funding", "donate", "sponsor", "tidelift":
+			module.Funding = append(module.Funding, Funding{URL: u})
+		}
+	}
+	if owner, ok := projectJSRepositoryOwner(module.Repository); ok {
+		module.Authors = append(module.Authors, owner)
+	}
+	if len(module.Authors) > 0 {
+		module.Author = module.Authors[0].displayName()
+	} else {
+		module.Author = module.Name + " authors"
+	}
+
+	module.LicenseName = pythonLicenseName(header)
+	module.LicenseContent = readPythonLicenseFiles(fsys, root, distInfo, header["License-File"])
+	if module.LicenseContent == "" {
+		fmt.Fprintf(os.Stderr, "%s: license file missing\n", module.Name)
+	}
+	switch {
+	case module.LicenseName != "":
+	case module.LicenseContent != "":
+		module.LicenseName = guessLicenseName(module.LicenseContent)
+	default:
+		module.LicenseName = "no license"
+	}
+	return nil
+}
+
+// readPythonLicenseFiles reads license files in dist-info. Files are searched in License-File fields
+// (dist-info/licenses/ for metadata 2.4, dist-info/ for older setuptools), then license-like files in RECORD.
+func readPythonLicenseFiles(fsys fs.FS, root, distInfo string, licenseFiles []string) string {
+	var candidates []string
+	for _, file := range licenseFiles {
+		candidates = append(candidates, path.Join("licenses", file), file, path.Base(file))
+	}
+	prefix := distInfo + "/"
+	for _, file := range pythonRecordFiles(fsys, root, distInfo) {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(file, prefix)
+		if strings.HasPrefix(rel, "licenses/") || isLicenseFileName(path.Base(rel)) {
+			candidates = append(candidates, rel)
+		}
+	}
+	var contents []string
+	used := map[string]bool{}
+	for _, candidate := range candidates {
+		content, err := fs.ReadFile(fsys, fsPath(root, distInfo, candidate))
+		if err != nil {
+			continue
+		}
+		text := strings.TrimSpace(string(content))
+		if !used[text] {
+			used[text] = true
+			contents = append(contents, text)
+		}
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonName normalizes the distribution name (PEP 503).
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+func pythonPURL(module Module) PURL {
+	return PURL{
+		Type:    "pypi",
+		Name:    normalizePythonName(module.Name),
+		Version: module.Version,
+	}
+}
+
+// ErrNotPyInstaller is returned when the executable doesn't have PyInstaller's archive.
+var ErrNotPyInstaller = errors.New("executable isn't built by PyInstaller")
+
+// ParsePyInstaller reads the distributions bundled into the PyInstaller executable.
+// Importable names in the PYZ archive and extension modules are matched with distributions in sitePackages
+// (the virtualenv that the executable was built from). dist-info folders that PyInstaller collected are matched too.
+func ParsePyInstaller(executable, sitePackages string) ([]Module, error) {
+	return ParsePyInstallerFS(DirFS(filepath.Dir(executable)), filepath.Base(executable),
+		DirFS(filepath.Dir(sitePackages)), filepath.Base(sitePackages))
+}
+
+// ParsePyInstallerFS reads the distributions bundled into the PyInstaller executable in fsys.
+// sitePackages is in siteFS because the executable and the virtualenv are often in different folders.
+func ParsePyInstallerFS(fsys fs.FS, executable string, siteFS fs.FS, sitePackages string) ([]Module, error) {
+	names, distInfos, err := readPyInstallerArchive(fsys, executable)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := ParsePythonSitePackagesFS(siteFS, sitePackages)
+	if err != nil {
+		return nil, err
+	}
+	var result []Module
+	for _, module := range installed {
+		distInfo := strings.TrimPrefix(module.Path, "/")
+		linked := distInfos[distInfo]
+		for _, name := range pythonTopLevelNames(siteFS, sitePackages, distInfo) {
+			if names[name] {
+				linked = true
+				break
+			}
+		}
+		if linked {
+			result = append(result, module)
+		}
+	}
+	return result, nil
+}
+
+// pyInstallerCookie is the magic of PyInstaller's CArchive cookie.
+var pyInstallerCookie = []byte("MEI\014\013\012\013\016")
+
+type pyInstallerEntry struct {
+	name       string
+	typ        byte
+	compressed bool
+	data       []byte
+}
+
+// readPyInstallerArchive returns top-level importable names and dist-info folders in the executable.
+func readPyInstallerArchive(fsys fs.FS, executable string) (map[string]bool, map[string]bool, error) {
+	content, err := fs.ReadFile(fsys, executable)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := readPyInstallerCArchive(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", executable, err)
+	}
+	names := map[string]bool{}
+	distInfos := map[string]bool{}
+	for _, entry := range entries {
+		switch entry.typ {
+		case 'z', 'Z':
+			data := entry.data
+			if entry.compressed {
+				r, err := zlib.NewReader(bytes.NewReader(data))
+				if err != nil {
+					return nil, nil, fmt.Errorf("%s: %s: %w", executable, entry.name, err)
+				}
+				data, err = ioutil.ReadAll(r)
+				if err != nil {
+					return nil, nil, fmt.Errorf("%s: %s: %w", executable, entry.name, err)
+				}
+			}
+			modules, err := readPYZNames(data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s: %w", executable, entry.name, err)
+			}
+			for _, module := range modules {
+				names[strings.SplitN(module, ".", 2)[0]] = true
+			}
+		case 'b', 'e', 'x', 'n', 'm', 'M':
+			// binaries, extension modules, data files and byte-compiled modules
+			name := strings.SplitN(filepath.ToSlash(entry.name), "/", 2)
+			if len(name) == 2 && strings.HasSuffix(name[0], ".dist-info") {
+				distInfos[name[0]] = true
+			} else if importName := pythonImportName(name[0]); importName != "" {
+				names[importName] = true
+			}
+		}
+	}
+	return names, distInfos, nil
+}
+
+// readPyInstallerCArchive reads the table of contents of the archive appended to the executable.
+//
+// See https://pyinstaller.org/en/stable/advanced-topics.html#carchive
+func readPyInstallerCArchive(content []byte) ([]pyInstallerEntry, error) {
+	cookiePos := bytes.LastIndex(content, pyInstallerCookie)
+	if cookiePos == -1 {
+		return nil, ErrNotPyInstaller
+	}
+	// cookie: magic, package length, TOC offset, TOC length, python version (and python library name since 2.1)
+	if cookiePos+24 > len(content) {
+		return nil, ErrNotPyInstaller
+	}
+	packageLength := int(be32(content[cookiePos+8:]))
+	tocOffset := int(be32(content[cookiePos+12:]))
+	tocLength := int(be32(content[cookiePos+16:]))
+	cookieSize := 88
+	if cookiePos+cookieSize > len(content) {
+		cookieSize = 24
+	}
+	packageStart := cookiePos + cookieSize - packageLength
+	if packageStart < 0 || packageStart+tocOffset+tocLength > len(content) {
+		return nil, errors.New("broken PyInstaller archive")
+	}
+	toc := content[packageStart+tocOffset : packageStart+tocOffset+tocLength]
+	var result []pyInstallerEntry
+	for len(toc) >= 18 {
+		entrySize := int(be32(toc))
+		if entrySize < 18 || entrySize > len(toc) {
+			return nil, errors.New("broken PyInstaller table of contents")
+		}
+		pos := packageStart + int(be32(toc[4:]))
+		size := int(be32(toc[8:]))
+		entry := pyInstallerEntry{
+			compressed: toc[16] == 1,
+			typ:        toc[17],
+			name:       string(bytes.TrimRight(toc[18:entrySize], "\x00")),
+		}
+		if pos < 0 || pos+size > len(content) {
+			return nil, fmt.Errorf("broken PyInstaller entry: %s", entry.name)
+		}
+		entry.data = content[pos : pos+size]
+		result = append(result, entry)
+		toc = toc[entrySize:]
+	}
+	return result, nil
+}
+
+func be32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// readPYZNames returns module names in the PYZ archive. The archive is "PYZ\0", pyc magic,
+// TOC offset and marshaled TOC ({name: (typecode, offset, length)} or the list of the pairs).
+func readPYZNames(data []byte) ([]string, error) {
+	if len(data) < 12 || !bytes.HasPrefix(data, []byte("PYZ\x00")) {
+		return nil, errors.New("invalid PYZ archive")
+	}
+	tocOffset := int(be32(data[8:]))
+	if tocOffset >= len(data) {
+		return nil, errors.New("invalid PYZ archive")
+	}
+	u := &pythonUnmarshaler{r: bytes.NewReader(data[tocOffset:])}
+	toc, err := u.read()
+	if err != nil {
+		return nil, err
+	}
+	var pairs []interface{}
+	switch toc := toc.(type) {
+	case pythonDict:
+		for _, item := range toc {
+			pairs = append(pairs, item[0])
+		}
+	case []interface{}:
+		for _, item := range toc {
+			if pair, ok := item.([]interface{}); ok && len(pair) == 2 {
+				pairs = append(pairs, pair[0])
+			}
+		}
+	default:
+		return nil, errors.New("unknown PYZ table of contents")
+	}
+	var result []string
+	for _, name := range pairs {
+		switch name := name.(type) {
+		case string:
+			result = append(result, name)
+		case []byte:
+			result = append(result, string(name))
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// pythonDict is the key-value pairs of Python's dict.
+type pythonDict [][2]interface{}
+
+// pythonUnmarshaler reads the subset of Python's marshal format that is used for PYZ's TOC.
+type pythonUnmarshaler struct {
+	r    *bytes.Reader
+	refs []interface{}
+}
+
+func (u *pythonUnmarshaler) int32() (int, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(u.r, b[:]); err != nil {
+		return 0, err
+	}
+	return int(int32(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24)), nil
+}
+
+func (u *pythonUnmarshaler) bytes(n int) ([]byte, error) {
+	if n < 0 || n > u.r.Len() {
+		return nil, errors.New("marshal: invalid length")
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(u.r, b)
+	return b, err
+}
+
+func (u *pythonUnmarshaler) read() (interface{}, error) {
+	code, err := u.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	const flagRef = 0x80
+	ref := code&flagRef != 0
+	code &^= flagRef
+	refIndex := len(u.refs)
+	if ref {
+		// reserve the slot before reading children
+		u.refs = append(u.refs, nil)
+	}
+	var value interface{}
+	switch code {
+	case '0', 'N':
+		value = nil
+	case 'T':
+		value = true
+	case 'F':
+		value = false
+	case 'i':
+		value, err = u.int32()
+	case 'g':
+		_, err = u.bytes(8)
+	case 'l':
+		var n int
+		n, err = u.int32()
+		if n < 0 {
+			n = -n
+		}
+		if err == nil {
+			_, err = u.bytes(n * 2)
+		}
+	case 's':
+		var n int
+		if n, err = u.int32(); err == nil {
+			value, err = u.bytes(n)
+		}
+	case 'u', 't', 'a', 'A':
+		var n int
+		var b []byte
+		if n, err = u.int32(); err == nil {
+			b, err = u.bytes(n)
+			value = string(b)
+		}
+	case 'z', 'Z':
+		var n byte
+		var b []byte
+		if n, err = u.r.ReadByte(); err == nil {
+			b, err = u.bytes(int(n))
+			value = string(b)
+		}
+	case '(', '[', '<', '>', ')':
+		var n int
+		if code == ')' {
+			var b byte
+			b, err = u.r.ReadByte()
+			n = int(b)
+		} else {
+			n, err = u.int32()
+		}
+		if err == nil && (n < 0 || n > u.r.Len()) {
+			err = errors.New("marshal: invalid length")
+		}
+		var items []interface{}
+		for i := 0; err == nil && i < n; i++ {
+			var item interface{}
+			item, err = u.read()
+			items = append(items, item)
+		}
+		value = items
+	case '{':
+		var dict pythonDict
+		for {
+			var key, item interface{}
+			if next, e := u.r.ReadByte(); e != nil {
+				err = e
+				break
+			} else if next == '0' {
+				break
+			}
+			u.r.UnreadByte()
+			if key, err = u.read(); err != nil {
+				break
+			}
+			if item, err = u.read(); err != nil {
+				break
+			}
+			dict = append(dict, [2]interface{}{key, item})
+		}
+		value = dict
+	case 'r':
+		var n int
+		if n, err = u.int32(); err == nil {
+			if n < 0 || n >= len(u.refs) {
+				err = errors.New("marshal: invalid reference")
+			} else {
+				value = u.refs[n]
+			}
+		}
+	default:
+		err = fmt.Errorf("marshal: unsupported type %q", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ref {
+		u.refs[refIndex] = value
+	}
+	return value, nil
+}
+
+func init() {
+	RegisterProjectDataReaderFS("python", projectPythonReader)
+	RegisterPURLBuilder("python", pythonPURL)
+}
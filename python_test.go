@@ -0,0 +1,159 @@
+package linkedpackage
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pythonSitePackages = filepath.Join("testdata", "python", "site-packages")
+
+func TestParsePythonSitePackages(t *testing.T) {
+	got, err := ParsePythonSitePackages(pythonSitePackages)
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Lang: "python", Name: "PyYAML", Version: "6.0", Path: "/PyYAML-6.0.dist-info"},
+		{Lang: "python", Name: "attrs", Version: "23.1.0", Path: "/attrs-23.1.0.dist-info"},
+		{Lang: "python", Name: "requests", Version: "2.26.0", Path: "/requests-2.26.0.dist-info"},
+		{Lang: "python", Name: "six", Version: "1.16.0", Path: "/six-1.16.0.dist-info"},
+	}, got)
+
+	_, err = ParsePythonSitePackages(filepath.Join("testdata", "python"))
+	assert.Error(t, err)
+}
+
+func TestParsePyInstaller(t *testing.T) {
+	got, err := ParsePyInstaller(filepath.Join("testdata", "python", "pyinstaller-app"), pythonSitePackages)
+	assert.NoError(t, err)
+	var names []string
+	for _, m := range got {
+		names = append(names, m.Name)
+	}
+	// six is installed but not bundled
+	assert.Equal(t, []string{"PyYAML", "attrs", "requests"}, names)
+
+	_, err = ParsePyInstaller(filepath.Join("testdata", "rust", "auditable.elf"), pythonSitePackages)
+	assert.True(t, errors.Is(err, ErrNotPyInstaller))
+}
+
+func Test_projectPythonReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		module Module
+		want   Module
+	}{
+		{
+			name:   "License and License-File in dist-info",
+			module: Module{Lang: "python", Name: "requests", Version: "2.26.0", Path: "/requests-2.26.0.dist-info"},
+			want: Module{
+				Lang:    "python",
+				Name:    "requests",
+				Version: "2.26.0",
+				Path:    "/requests-2.26.0.dist-info",
+				Author:  "Kenneth Reitz <me@kennethreitz.org>",
+				Authors: []Author{
+					{Name: "Kenneth Reitz", Email: "me@kennethreitz.org", Role: AuthorRoleAuthor},
+					{Name: "psf", URL: "https://github.com/psf", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "Apache 2.0",
+				LicenseContent: "Apache License\n                           Version 2.0, January 2004",
+				Description:    "Python HTTP for Humans.",
+				Homepage:       "https://requests.readthedocs.io",
+				Repository:     "https://github.com/psf/requests",
+			},
+		},
+		{
+			name:   "License-Expression and licenses folder",
+			module: Module{Lang: "python", Name: "attrs", Version: "23.1.0", Path: "/attrs-23.1.0.dist-info"},
+			want: Module{
+				Lang:    "python",
+				Name:    "attrs",
+				Version: "23.1.0",
+				Path:    "/attrs-23.1.0.dist-info",
+				Author:  "Hynek Schlawack <hs@ox.cx>",
+				Authors: []Author{
+					{Name: "Hynek Schlawack", Email: "hs@ox.cx", Role: AuthorRoleAuthor},
+					{Name: "python-attrs", URL: "https://github.com/python-attrs", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "MIT",
+				LicenseContent: "The MIT License (MIT)\n\nCopyright (c) 2015 Hynek Schlawack and the attrs contributors",
+				Description:    "Classes Without Boilerplate",
+				Repository:     "https://github.com/python-attrs/attrs",
+				Funding: []Funding{
+					{URL: "https://github.com/sponsors/hynek"},
+					{URL: "https://tidelift.com/subscription/pkg/pypi-attrs"},
+				},
+			},
+		},
+		{
+			name:   "classifier",
+			module: Module{Lang: "python", Name: "PyYAML", Version: "6.0", Path: "/PyYAML-6.0.dist-info"},
+			want: Module{
+				Lang:    "python",
+				Name:    "PyYAML",
+				Version: "6.0",
+				Path:    "/PyYAML-6.0.dist-info",
+				Author:  "Kirill Simonov <xi@resolvent.net>",
+				Authors: []Author{
+					{Name: "Kirill Simonov", Email: "xi@resolvent.net", Role: AuthorRoleAuthor},
+				},
+				LicenseName:    "MIT",
+				LicenseContent: "Copyright (c) 2017-2021 Ingy döt Net\n\nPermission is hereby granted, free of charge, to any person obtaining a copy of\nthis software and associated documentation files (the \"Software\").",
+				Description:    "YAML parser and emitter for Python",
+				Homepage:       "https://pyyaml.org/",
+				Bugs:           "https://github.com/yaml/pyyaml/issues",
+			},
+		},
+		{
+			name:   "no license file",
+			module: Module{Lang: "python", Name: "six", Version: "1.16.0", Path: "/six-1.16.0.dist-info"},
+			want: Module{
+				Lang:    "python",
+				Name:    "six",
+				Version: "1.16.0",
+				Path:    "/six-1.16.0.dist-info",
+				Author:  "Benjamin Peterson <benjamin@python.org>",
+				Authors: []Author{
+					{Name: "Benjamin Peterson", Email: "benjamin@python.org", Role: AuthorRoleAuthor},
+				},
+				LicenseName: "MIT",
+				Description: "Python 2 and 3 compatibility utilities",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, pythonSitePackages))
+			assert.Equal(t, tt.want, module)
+		})
+	}
+}
+
+func Test_pythonTopLevelNames(t *testing.T) {
+	assert.Equal(t, []string{"_yaml", "yaml"}, pythonTopLevelNames(DirFS(pythonSitePackages), ".", "PyYAML-6.0.dist-info"))
+	assert.Equal(t, []string{"attr", "attrs"}, pythonTopLevelNames(DirFS(pythonSitePackages), ".", "attrs-23.1.0.dist-info"))
+	assert.Equal(t, []string{"six"}, pythonTopLevelNames(DirFS(pythonSitePackages), ".", "six-1.16.0.dist-info"))
+}
+
+func Test_readPYZNames(t *testing.T) {
+	// {"a.b": (0, 1, 2), "c": (1, 3, 4)} marshaled with references
+	toc := "PYZ\x00\x55\x0d\x0d\x0a\x00\x00\x00\x0c" +
+		"\xfb" + "\xda\x03a.b" + "\xa9\x03" + "\xe9\x00\x00\x00\x00" + "i\x01\x00\x00\x00" + "i\x02\x00\x00\x00" +
+		"\xda\x01c" + "\xa9\x03" + "i\x01\x00\x00\x00" + "i\x03\x00\x00\x00" + "i\x04\x00\x00\x00" + "0"
+	got, err := readPYZNames([]byte(toc))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.b", "c"}, got)
+
+	_, err = readPYZNames([]byte("PK\x03\x04"))
+	assert.Error(t, err)
+}
+
+func Test_pythonPURL(t *testing.T) {
+	m := Module{Lang: "python", Name: "PyYAML", Version: "6.0"}
+	assert.Equal(t, "pkg:pypi/pyyaml@6.0", m.PURL())
+	assert.True(t, strings.HasPrefix((Module{Lang: "python", Name: "zope.interface"}).PURL(), "pkg:pypi/zope-interface"))
+}
@@ -0,0 +1,277 @@
+package linkedpackage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoAuditableData is returned when the binary isn't built by `cargo auditable`.
+var ErrNoAuditableData = errors.New("binary doesn't have cargo auditable data")
+
+// cargoAuditableData is the dependency tree embedded by `cargo auditable`.
+//
+// See https://github.com/rust-secure-code/cargo-auditable/blob/master/PARSING.md
+type cargoAuditableData struct {
+	Packages []cargoAuditablePackage `json:"packages"`
+}
+
+type cargoAuditablePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// Kind is "runtime" (default) or "build". Build dependencies aren't linked into the binary
+	Kind string `json:"kind"`
+	Root bool   `json:"root"`
+}
+
+// ParseRustBinary reads crates linked into the executable from the ".dep-v0" section that `cargo auditable` embeds.
+// ELF, PE and Mach-O binaries are supported. It returns ErrNoAuditableData if the section is missing.
+// Module.Path is the crate folder name ("/<name>-<version>").
+func ParseRustBinary(path string) ([]Module, error) {
+	return ParseRustBinaryFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseRustBinaryFS reads crates linked into the executable in the file system.
+func ParseRustBinaryFS(fsys fs.FS, name string) ([]Module, error) {
+	f, closer, err := openReaderAt(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	compressed, err := readAuditableSection(f, name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer r.Close()
+	var data cargoAuditableData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var result []Module
+	for _, pkg := range data.Packages {
+		if pkg.Root || pkg.Kind == "build" {
+			continue
+		}
+		result = append(result, Module{
+			Lang:    "rust",
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Path:    "/" + pkg.Name + "-" + pkg.Version,
+		})
+	}
+	return result, nil
+}
+
+func readAuditableSection(r io.ReaderAt, name string) ([]byte, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		if section := f.Section(".dep-v0"); section != nil {
+			return section.Data()
+		}
+		return nil, ErrNoAuditableData
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		if section := f.Section(".dep-v0"); section != nil {
+			data, err := section.Data()
+			if err != nil {
+				return nil, err
+			}
+			// PE sections are padded to the file alignment
+			if int(section.VirtualSize) < len(data) {
+				data = data[:section.VirtualSize]
+			}
+			return data, nil
+		}
+		return nil, ErrNoAuditableData
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		if section := f.Section("__dep_v0"); section != nil {
+			return section.Data()
+		}
+		return nil, ErrNoAuditableData
+	}
+	return nil, fmt.Errorf("%s: unsupported executable format", name)
+}
+
+// ParseCargoLock reads crates from Cargo.lock. Crates without source (workspace members) are skipped.
+func ParseCargoLock(path string) ([]Module, error) {
+	return ParseCargoLockFS(DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseCargoLockFS reads crates from Cargo.lock in the file system.
+func ParseCargoLockFS(fsys fs.FS, name string) ([]Module, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tables, err := parseTOML(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var result []Module
+	for _, table := range tables {
+		if table.Name != "package" || !table.Array || table.String("source") == "" {
+			continue
+		}
+		module := Module{
+			Lang:    "rust",
+			Name:    table.String("name"),
+			Version: table.String("version"),
+		}
+		module.Path = "/" + module.Name + "-" + module.Version
+		if checksum, err := hex.DecodeString(table.String("checksum")); err == nil && len(checksum) > 0 {
+			module.Integrity = "sha256-" + base64.StdEncoding.EncodeToString(checksum)
+		}
+		result = append(result, module)
+	}
+	return result, nil
+}
+
+// cargoRegistrySrc returns the folder that has extracted crates ($CARGO_HOME/registry/src).
+func cargoRegistrySrc() string {
+	cargoHome := os.Getenv("CARGO_HOME")
+	if cargoHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		cargoHome = filepath.Join(home, ".cargo")
+	}
+	return filepath.Join(cargoHome, "registry", "src")
+}
+
+// projectRustReader reads Cargo.toml and license of the crate. root is the registry's src folder
+// that contains "<registry>-<hash>/<name>-<version>". $CARGO_HOME/registry/src is used if root is empty.
+func projectRustReader(module *Module, root string) error {
+	if root == "" {
+		root = cargoRegistrySrc()
+	}
+	return projectRustReaderFS(module, DirFS(root), ".")
+}
+
+// projectRustReaderFS reads Cargo.toml and license of the crate. root is the registry's src folder in the file system.
+func projectRustReaderFS(module *Module, fsys fs.FS, root string) error {
+	crateDir := strings.TrimPrefix(module.Path, "/")
+	if i := strings.LastIndex(crateDir, "/"); i != -1 {
+		crateDir = crateDir[i+1:]
+	}
+	matches, _ := fs.Glob(fsys, fsPath(root, "*", crateDir, "Cargo.toml"))
+	if len(matches) == 0 {
+		return fmt.Errorf("%s: crate is not found in %s", module.Name, root)
+	}
+	dir := path.Dir(matches[0])
+	rel := dir
+	if base := fsPath(root); base != "." {
+		rel = strings.TrimPrefix(dir, base+"/")
+	}
+	module.Path = "/" + rel
+
+	f, err := fsys.Open(matches[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tables, err := parseTOML(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", matches[0], err)
+	}
+	pkg, ok := tomlFind(tables, "package")
+	if !ok {
+		return fmt.Errorf("%s: [package] is missing", matches[0])
+	}
+
+	for _, author := range pkg.Strings("authors") {
+		a := parseAuthorString(author)
+		if a.Name == "" && a.Email == "" {
+			continue
+		}
+		a.Role = AuthorRoleAuthor
+		module.Authors = append(module.Authors, a)
+	}
+	module.Description = strings.TrimSpace(pkg.String("description"))
+	module.Homepage = pkg.String("homepage")
+	module.Repository = normalizeJSRepositoryURL(pkg.String("repository"))
+	if owner, ok := projectJSRepositoryOwner(module.Repository); ok {
+		module.Authors = append(module.Authors, owner)
+	}
+	switch {
+	case len(module.Authors) > 0:
+		module.Author = module.Authors[0].displayName()
+	default:
+		module.Author = module.Name + " authors"
+	}
+
+	if license := pkg.String("license"); license != "" {
+		module.LicenseName = normalizeCargoLicense(license)
+	}
+	// `cargo package` copies the license file out of the crate into the crate root, so the file must be in the crate
+	if file, ok := packageFilePath(pkg.String("license-file")); ok {
+		if module.LicenseName == "" {
+			module.LicenseName = licenseRefID(module.Name, file)
+		}
+		module.LicenseFile = file
+		content, err := fs.ReadFile(fsys, fsPath(dir, file))
+		if err == nil {
+			module.LicenseContent = strings.TrimSpace(string(content))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
+	}
+	if module.LicenseContent == "" {
+		if err := module.readLicenseFS(fsys, root); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", module.Name, err.Error())
+		}
+	}
+	if module.LicenseName == "" {
+		if module.LicenseContent != "" {
+			module.LicenseName = guessLicenseName(module.LicenseContent)
+		} else {
+			module.LicenseName = "no license"
+		}
+	}
+	return nil
+}
+
+// normalizeCargoLicense converts the deprecated "/" separator ("MIT/Apache-2.0") into SPDX's "OR".
+func normalizeCargoLicense(license string) string {
+	if !strings.Contains(license, "/") {
+		return license
+	}
+	var names []string
+	for _, name := range strings.Split(license, "/") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return strings.Join(names, " OR ")
+}
+
+func rustPURL(module Module) PURL {
+	return PURL{
+		Type:    "cargo",
+		Name:    module.Name,
+		Version: module.Version,
+	}
+}
+
+func init() {
+	RegisterProjectDataReader("rust", projectRustReader)
+	RegisterProjectDataReaderFS("rust", projectRustReaderFS)
+	RegisterPURLBuilder("rust", rustPURL)
+}
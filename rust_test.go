@@ -0,0 +1,159 @@
+package linkedpackage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRustBinary(t *testing.T) {
+	got, err := ParseRustBinary(filepath.Join("testdata", "rust", "auditable.elf"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Lang: "rust", Name: "itoa", Version: "1.0.1", Path: "/itoa-1.0.1"},
+		{Lang: "rust", Name: "legacy-dual", Version: "0.1.0", Path: "/legacy-dual-0.1.0"},
+	}, got)
+
+	// Go test binary isn't built by cargo auditable
+	_, err = ParseRustBinary(os.Args[0])
+	assert.Equal(t, ErrNoAuditableData, err)
+
+	_, err = ParseRustBinary(filepath.Join("testdata", "rust", "Cargo.lock"))
+	assert.Error(t, err)
+}
+
+func TestParseCargoLock(t *testing.T) {
+	got, err := ParseCargoLock(filepath.Join("testdata", "rust", "Cargo.lock"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Lang: "rust", Name: "itoa", Version: "1.0.1", Path: "/itoa-1.0.1", Integrity: "sha256-GquPw2dYi4nc7oOrD9ZrcrULcvoZBNcJUEWs4rDIHDU="},
+		{Lang: "rust", Name: "legacy-dual", Version: "0.1.0", Path: "/legacy-dual-0.1.0"},
+	}, got)
+}
+
+func Test_projectRustReader(t *testing.T) {
+	root := filepath.Join("testdata", "cargo-registry", "src")
+	tests := []struct {
+		name   string
+		module Module
+		want   Module
+	}{
+		{
+			name:   "registry crate",
+			module: Module{Lang: "rust", Name: "itoa", Version: "1.0.1", Path: "/itoa-1.0.1"},
+			want: Module{
+				Lang:    "rust",
+				Name:    "itoa",
+				Version: "1.0.1",
+				Path:    "/index.crates.io-6f17d22bba15001f/itoa-1.0.1",
+				Author:  "David Tolnay <dtolnay@gmail.com>",
+				Authors: []Author{
+					{Name: "David Tolnay", Email: "dtolnay@gmail.com", Role: AuthorRoleAuthor},
+					{Name: "dtolnay", URL: "https://github.com/dtolnay", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "MIT OR Apache-2.0",
+				LicenseContent: "Permission is hereby granted, free of charge, to any\nperson obtaining a copy of this software and associated\ndocumentation files (the \"Software\"), to deal in the\nSoftware without restriction.",
+				Description:    "Fast integer primitive to string conversion",
+				Repository:     "https://github.com/dtolnay/itoa",
+			},
+		},
+		{
+			name:   "deprecated license separator",
+			module: Module{Lang: "rust", Name: "legacy-dual", Version: "0.1.0", Path: "/legacy-dual-0.1.0"},
+			want: Module{
+				Lang:    "rust",
+				Name:    "legacy-dual",
+				Version: "0.1.0",
+				Path:    "/index.crates.io-6f17d22bba15001f/legacy-dual-0.1.0",
+				Author:  "example",
+				Authors: []Author{
+					{Name: "example", URL: "https://github.com/example", Role: AuthorRoleOwner},
+				},
+				LicenseName:    "MIT OR Apache-2.0",
+				LicenseContent: "Licensed under either of Apache License, Version 2.0 or MIT license at your option.",
+				Description:    "Crate that uses the deprecated license separator.",
+				Repository:     "https://github.com/example/legacy-dual",
+			},
+		},
+		{
+			name:   "license file",
+			module: Module{Lang: "rust", Name: "custom-license", Version: "0.2.0", Path: "/custom-license-0.2.0"},
+			want: Module{
+				Lang:    "rust",
+				Name:    "custom-license",
+				Version: "0.2.0",
+				Path:    "/index.crates.io-6f17d22bba15001f/custom-license-0.2.0",
+				Author:  "Acme Corp <oss@acme.example>",
+				Authors: []Author{
+					{Name: "Acme Corp", Email: "oss@acme.example", Role: AuthorRoleAuthor},
+				},
+				LicenseName:    "LicenseRef-custom-license-EULA.txt",
+				LicenseFile:    "EULA.txt",
+				LicenseContent: "Acme End User License Agreement",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := tt.module
+			assert.NoError(t, ReadProjectData(&module, root))
+			assert.Equal(t, tt.want, module)
+
+			module = tt.module
+			assert.NoError(t, ReadProjectDataFS(&module, DirFS("testdata"), "cargo-registry/src"))
+			assert.Equal(t, tt.want, module)
+		})
+	}
+
+	missing := Module{Lang: "rust", Name: "missing", Version: "1.0.0", Path: "/missing-1.0.0"}
+	assert.Error(t, ReadProjectData(&missing, root))
+
+	// the license file out of the crate is not read
+	fsys := NewMemFS(map[string][]byte{
+		"src/index.crates.io-6f17d22bba15001f/evil-0.1.0/Cargo.toml": []byte("[package]\nname = \"evil\"\nversion = \"0.1.0\"\nlicense-file = \"../../../secret.txt\"\n"),
+		"secret.txt": []byte("secret"),
+	})
+	evil := Module{Lang: "rust", Name: "evil", Version: "0.1.0", Path: "/evil-0.1.0"}
+	assert.NoError(t, ReadProjectDataFS(&evil, fsys, "src"))
+	assert.Empty(t, evil.LicenseFile)
+	assert.NotContains(t, evil.LicenseContent, "secret")
+}
+
+func Test_parseTOML(t *testing.T) {
+	tables, err := parseTOML(strings.NewReader(`name = "root" # comment
+[package]
+authors = [
+    "A <a@example.com>", # first
+    'B',
+]
+description = """
+multi
+line"""
+edition = 2018
+
+[[bin]]
+name = "one"
+
+[[bin]]
+name = "two"
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []tomlTable{
+		{Values: map[string]interface{}{"name": "root"}},
+		{Name: "package", Values: map[string]interface{}{
+			"authors":     []string{"A <a@example.com>", "B"},
+			"description": "multi\nline",
+			"edition":     "2018",
+		}},
+		{Name: "bin", Array: true, Values: map[string]interface{}{"name": "one"}},
+		{Name: "bin", Array: true, Values: map[string]interface{}{"name": "two"}},
+	}, tables)
+}
+
+func Test_rustPURL(t *testing.T) {
+	m := Module{Lang: "rust", Name: "itoa", Version: "1.0.1"}
+	assert.Equal(t, "pkg:cargo/itoa@1.0.1", m.PURL())
+}
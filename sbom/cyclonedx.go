@@ -0,0 +1,164 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/future-architect/linkedpackage"
+)
+
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp"`
+	Tools     []cycloneDXTool     `json:"tools"`
+	Component *cycloneDXComponent `json:"component,omitempty"`
+}
+
+type cycloneDXTool struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Author             string                       `json:"author,omitempty"`
+	Group              string                       `json:"group,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Description        string                       `json:"description,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	Hashes             []cycloneDXHash              `json:"hashes,omitempty"`
+	Licenses           []cycloneDXLicenseChoice     `json:"licenses,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty          `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXLicenseChoice struct {
+	License    *cycloneDXLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+type cycloneDXLicense struct {
+	ID   string                `json:"id,omitempty"`
+	Name string                `json:"name,omitempty"`
+	Text *cycloneDXLicenseText `json:"text,omitempty"`
+}
+
+type cycloneDXLicenseText struct {
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXExternalReference struct {
+	Type    string `json:"type"`
+	URL     string `json:"url"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// WriteCycloneDX writes modules as CycloneDX 1.5 JSON document.
+func WriteCycloneDX(w io.Writer, modules []linkedpackage.Module, opt Options) error {
+	doc := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + opt.uuid(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: opt.created(),
+			Tools:     []cycloneDXTool{{Name: toolName}},
+		},
+		Components: []cycloneDXComponent{},
+	}
+	if opt.Name != "" {
+		doc.Metadata.Component = &cycloneDXComponent{
+			Type:    "application",
+			Name:    opt.Name,
+			Version: opt.Version,
+		}
+	}
+	usedRefs := map[string]bool{}
+	for _, m := range modules {
+		c := cycloneDXComponent{
+			Type:        "library",
+			Author:      m.Author,
+			Name:        m.Name,
+			Version:     m.Version,
+			Description: m.Description,
+			PURL:        m.PURL(),
+		}
+		// purl is used as bom-ref unless same package is installed in several paths
+		c.BOMRef = c.PURL
+		if c.BOMRef == "" || usedRefs[c.BOMRef] {
+			c.BOMRef = m.Lang + ":" + m.Path
+		}
+		usedRefs[c.BOMRef] = true
+		if m.Lang == "js" && strings.HasPrefix(m.Name, "@") {
+			if group, name, ok := strings.Cut(m.Name, "/"); ok {
+				c.Group = group
+				c.Name = name
+			}
+		}
+		for _, h := range m.Checksums() {
+			if alg, ok := hashAlgorithms[h.Algorithm]; ok {
+				c.Hashes = append(c.Hashes, cycloneDXHash{
+					Alg:     alg.cyclonedx,
+					Content: h.Value,
+				})
+			}
+		}
+		switch {
+		case m.Proprietary:
+			c.Licenses = []cycloneDXLicenseChoice{{License: &cycloneDXLicense{Name: linkedpackage.UnlicensedLicenseName}}}
+		case m.LicenseFile != "":
+			license := &cycloneDXLicense{Name: m.LicenseName}
+			if m.LicenseContent != "" {
+				license.Text = &cycloneDXLicenseText{Content: m.LicenseContent}
+			}
+			c.Licenses = []cycloneDXLicenseChoice{{License: license}}
+		case isLicenseExpression(m.LicenseName) && isCompoundLicenseExpression(m.LicenseName):
+			c.Licenses = []cycloneDXLicenseChoice{{Expression: m.LicenseName}}
+		case isLicenseExpression(m.LicenseName):
+			c.Licenses = []cycloneDXLicenseChoice{{License: &cycloneDXLicense{ID: m.LicenseName}}}
+		case m.LicenseName != "":
+			c.Licenses = []cycloneDXLicenseChoice{{License: &cycloneDXLicense{Name: m.LicenseName}}}
+		}
+		if m.Homepage != "" {
+			c.ExternalReferences = append(c.ExternalReferences, cycloneDXExternalReference{Type: "website", URL: m.Homepage})
+		}
+		if m.Repository != "" {
+			c.ExternalReferences = append(c.ExternalReferences, cycloneDXExternalReference{Type: "vcs", URL: m.Repository})
+		}
+		if m.Bugs != "" {
+			c.ExternalReferences = append(c.ExternalReferences, cycloneDXExternalReference{Type: "issue-tracker", URL: m.Bugs})
+		}
+		for _, f := range m.Funding {
+			c.ExternalReferences = append(c.ExternalReferences, cycloneDXExternalReference{Type: "other", URL: f.URL, Comment: "funding"})
+		}
+		if m.DirHash != "" {
+			// hashes are the hashes of the package file, so the hash of the installed folder is a property
+			c.Properties = append(c.Properties, cycloneDXProperty{Name: dirHashProperty, Value: m.DirHash})
+		}
+		doc.Components = append(doc.Components, c)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,46 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCycloneDX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCycloneDX(&buf, testModules, testOptions)
+	assert.NoError(t, err)
+
+	var doc cycloneDXDocument
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "1.5", doc.SpecVersion)
+	assert.Equal(t, "urn:uuid:6f1a0d64-4f5c-4d1e-9a3b-0c2d7e8f9a10", doc.SerialNumber)
+	assert.Equal(t, "sample-app", doc.Metadata.Component.Name)
+	assert.Len(t, doc.Components, 4)
+
+	foo := doc.Components[0]
+	assert.Equal(t, "pkg:npm/foo@1.2.3", foo.PURL)
+	assert.Equal(t, "pkg:npm/foo@1.2.3", foo.BOMRef)
+	assert.Equal(t, []cycloneDXHash{
+		{Alg: "SHA-1", Content: "62ce07900e6b17d6081c9dc5d4cdc79f6169ece7"},
+	}, foo.Hashes)
+	assert.Equal(t, []cycloneDXProperty{
+		{Name: "linkedpackage:dirhash", Value: "h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60="},
+	}, foo.Properties)
+	assert.Equal(t, []cycloneDXLicenseChoice{{License: &cycloneDXLicense{ID: "MIT"}}}, foo.Licenses)
+	assert.Equal(t, []cycloneDXExternalReference{
+		{Type: "website", URL: "https://foo.example.com"},
+		{Type: "vcs", URL: "https://github.com/example/foo"},
+	}, foo.ExternalReferences)
+
+	scoped := doc.Components[1]
+	assert.Equal(t, "@acme", scoped.Group)
+	assert.Equal(t, "ui", scoped.Name)
+	assert.Equal(t, "pkg:npm/%40acme/ui@2.1.0", scoped.PURL)
+	assert.Equal(t, "ACME END USER LICENSE AGREEMENT", scoped.Licenses[0].License.Text.Content)
+
+	assert.Equal(t, "UNLICENSED", doc.Components[2].Licenses[0].License.Name)
+	assert.Equal(t, "(MIT OR Apache-2.0)", doc.Components[3].Licenses[0].Expression)
+}
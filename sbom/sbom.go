@@ -0,0 +1,101 @@
+// Package sbom exports linked modules as Software Bill of Materials (SPDX and CycloneDX).
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/future-architect/linkedpackage"
+)
+
+type Options struct {
+	// Name is the name of the application that is described by the SBOM
+	Name string
+	// Version is the version of the application
+	Version string
+	// Created is the creation time of the document. Current time is used if it is zero
+	Created time.Time
+	// UUID is used for SPDX's document namespace and CycloneDX's serial number. Random UUID is used if it is empty
+	UUID string
+}
+
+func (o Options) created() string {
+	if o.Created.IsZero() {
+		return time.Now().UTC().Format(time.RFC3339)
+	}
+	return o.Created.UTC().Format(time.RFC3339)
+}
+
+func (o Options) uuid() string {
+	if o.UUID != "" {
+		return o.UUID
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+const toolName = "linkedpackage"
+
+// dirHashProperty is the CycloneDX property name of Module.DirHash. The namespace is the tool name.
+const dirHashProperty = toolName + ":dirhash"
+
+var licenseIDPattern = regexp.MustCompile(`^[A-Za-z0-9.\-+:]+$`)
+
+// isLicenseExpression checks the license name is syntactically valid SPDX license expression.
+// It doesn't check whether the identifiers are on the SPDX license list.
+func isLicenseExpression(license string) bool {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(license))
+	if len(tokens) == 0 {
+		return false
+	}
+	depth := 0
+	expectOperand := true
+	for _, token := range tokens {
+		switch {
+		case token == "(":
+			if !expectOperand {
+				return false
+			}
+			depth++
+		case token == ")":
+			if expectOperand || depth == 0 {
+				return false
+			}
+			depth--
+		case token == "AND" || token == "OR" || token == "WITH":
+			if expectOperand {
+				return false
+			}
+			expectOperand = true
+		case licenseIDPattern.MatchString(token):
+			if !expectOperand {
+				return false
+			}
+			expectOperand = false
+		default:
+			return false
+		}
+	}
+	return depth == 0 && !expectOperand
+}
+
+// isCompoundLicenseExpression returns true when the expression has operators.
+func isCompoundLicenseExpression(license string) bool {
+	return strings.ContainsAny(license, "() ")
+}
+
+// proprietaryLicenseRef is used for UNLICENSED modules.
+const proprietaryLicenseRef = "LicenseRef-" + linkedpackage.UnlicensedLicenseName
+
+var hashAlgorithms = map[string]struct{ spdx, cyclonedx string }{
+	"SHA1":   {"SHA1", "SHA-1"},
+	"SHA256": {"SHA256", "SHA-256"},
+	"SHA384": {"SHA384", "SHA-384"},
+	"SHA512": {"SHA512", "SHA-512"},
+}
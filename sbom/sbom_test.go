@@ -0,0 +1,78 @@
+package sbom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/future-architect/linkedpackage"
+)
+
+var testOptions = Options{
+	Name:    "sample-app",
+	Version: "1.0.0",
+	Created: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC),
+	UUID:    "6f1a0d64-4f5c-4d1e-9a3b-0c2d7e8f9a10",
+}
+
+var testModules = []linkedpackage.Module{
+	{
+		Lang:        "js",
+		Name:        "foo",
+		Path:        "/node_modules/foo",
+		Author:      "foo author <foo@example.com>",
+		Authors:     []linkedpackage.Author{{Name: "foo author", Email: "foo@example.com", Role: linkedpackage.AuthorRoleAuthor}},
+		LicenseName: "MIT",
+		Version:     "1.2.3",
+		Homepage:    "https://foo.example.com",
+		Repository:  "https://github.com/example/foo",
+		Integrity:   "sha1-Ys4HkA5rF9YIHJ3F1M3Hn2Fp7Oc=",
+		DirHash:     "h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60=",
+	},
+	{
+		Lang:           "js",
+		Name:           "@acme/ui",
+		Path:           "/node_modules/@acme/ui",
+		LicenseName:    "LicenseRef-acme-ui-EULA.md",
+		LicenseFile:    "EULA.md",
+		LicenseContent: "ACME END USER LICENSE AGREEMENT",
+		Version:        "2.1.0",
+	},
+	{
+		Lang:        "js",
+		Name:        "internal-utils",
+		Path:        "/node_modules/internal-utils",
+		LicenseName: "UNLICENSED",
+		Proprietary: true,
+		Version:     "0.1.0",
+	},
+	{
+		Lang:        "js",
+		Name:        "dual",
+		Path:        "/node_modules/dual",
+		LicenseName: "(MIT OR Apache-2.0)",
+		Version:     "0.0.1",
+	},
+}
+
+func Test_isLicenseExpression(t *testing.T) {
+	tests := []struct {
+		license string
+		want    bool
+	}{
+		{license: "MIT", want: true},
+		{license: "(MIT OR Apache-2.0)", want: true},
+		{license: "GPL-2.0-or-later WITH Classpath-exception-2.0", want: true},
+		{license: "LicenseRef-acme-ui-EULA.md", want: true},
+		{license: "AFLv2.1, BSD", want: false},
+		{license: "no license", want: false},
+		{license: "(MIT OR", want: false},
+		{license: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			if got := isLicenseExpression(tt.license); got != tt.want {
+				t.Errorf("isLicenseExpression(%q) = %v, want %v", tt.license, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/future-architect/linkedpackage"
+)
+
+const noAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SPDXVersion          string                 `json:"spdxVersion"`
+	DataLicense          string                 `json:"dataLicense"`
+	SPDXID               string                 `json:"SPDXID"`
+	Name                 string                 `json:"name"`
+	DocumentNamespace    string                 `json:"documentNamespace"`
+	CreationInfo         spdxCreationInfo       `json:"creationInfo"`
+	Packages             []spdxPackage          `json:"packages"`
+	Relationships        []spdxRelationship     `json:"relationships"`
+	ExtractedLicenseInfo []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Originator       string            `json:"originator,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	Homepage         string            `json:"homepage,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Description      string            `json:"description,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name,omitempty"`
+}
+
+// WriteSPDX writes modules as SPDX 2.3 JSON document.
+func WriteSPDX(w io.Writer, modules []linkedpackage.Module, opt Options) error {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              opt.Name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + spdxID(opt.Name) + "-" + opt.uuid(),
+		CreationInfo: spdxCreationInfo{
+			Created:  opt.created(),
+			Creators: []string{"Tool: " + toolName},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+	root := spdxPackage{
+		SPDXID:           "SPDXRef-Application",
+		Name:             opt.Name,
+		VersionInfo:      opt.Version,
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+	}
+	doc.Packages = append(doc.Packages, root)
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SPDXElementID:      doc.SPDXID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: root.SPDXID,
+	})
+
+	usedIDs := map[string]bool{root.SPDXID: true}
+	extracted := map[string]bool{}
+	for _, m := range modules {
+		baseID := "SPDXRef-Package-" + spdxID(m.Lang+"-"+strings.TrimPrefix(m.Name, "@")+"-"+m.Version)
+		id := baseID
+		for i := 2; usedIDs[id]; i++ {
+			id = baseID + "-" + strconv.Itoa(i)
+		}
+		usedIDs[id] = true
+
+		license := noAssertion
+		switch {
+		case m.Proprietary:
+			license = proprietaryLicenseRef
+			if !extracted[license] {
+				extracted[license] = true
+				doc.ExtractedLicenseInfo = append(doc.ExtractedLicenseInfo, spdxExtractedLicense{
+					LicenseID:     license,
+					ExtractedText: "This package is not licensed for use by others (" + linkedpackage.UnlicensedLicenseName + ").",
+					Name:          linkedpackage.UnlicensedLicenseName,
+				})
+			}
+		case m.LicenseFile != "":
+			license = m.LicenseName
+			if !extracted[license] {
+				extracted[license] = true
+				text := m.LicenseContent
+				if text == "" {
+					text = "See " + m.LicenseFile + " in " + m.Name
+				}
+				doc.ExtractedLicenseInfo = append(doc.ExtractedLicenseInfo, spdxExtractedLicense{
+					LicenseID:     license,
+					ExtractedText: text,
+					Name:          m.LicenseFile,
+				})
+			}
+		case isLicenseExpression(m.LicenseName):
+			license = m.LicenseName
+		}
+
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             m.Name,
+			VersionInfo:      m.Version,
+			DownloadLocation: noAssertion,
+			Homepage:         m.Homepage,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    noAssertion,
+			Description:      m.Description,
+		}
+		for _, a := range m.Authors {
+			if a.Role == linkedpackage.AuthorRoleAuthor && a.Name != "" {
+				pkg.Originator = "Person: " + a.Name
+				if a.Email != "" {
+					pkg.Originator += " (" + a.Email + ")"
+				}
+				break
+			}
+		}
+		for _, c := range m.Checksums() {
+			if alg, ok := hashAlgorithms[c.Algorithm]; ok {
+				pkg.Checksums = append(pkg.Checksums, spdxChecksum{
+					Algorithm:     alg.spdx,
+					ChecksumValue: c.Value,
+				})
+			}
+		}
+		if purl := m.PURL(); purl != "" {
+			pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  purl,
+			})
+		}
+		var comments []string
+		if m.DirHash != "" {
+			// SPDX's checksums are the hashes of the package file, and packageVerificationCode needs the file list
+			comments = append(comments, "Directory hash: "+m.DirHash)
+		}
+		if m.Repository != "" {
+			comments = append(comments, "Repository: "+m.Repository)
+		}
+		if m.Bugs != "" {
+			comments = append(comments, "Bugs: "+m.Bugs)
+		}
+		for _, f := range m.Funding {
+			comments = append(comments, "Funding: "+f.URL)
+		}
+		pkg.Comment = strings.Join(comments, "\n")
+
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      root.SPDXID,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// spdxID converts the string into idstring that contains only letters, numbers, "." and "-".
+func spdxID(src string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, src)
+}
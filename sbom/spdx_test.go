@@ -0,0 +1,45 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSPDX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteSPDX(&buf, testModules, testOptions)
+	assert.NoError(t, err)
+
+	var doc spdxDocument
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "https://spdx.org/spdxdocs/sample-app-6f1a0d64-4f5c-4d1e-9a3b-0c2d7e8f9a10", doc.DocumentNamespace)
+	assert.Equal(t, "2021-04-01T12:00:00Z", doc.CreationInfo.Created)
+	assert.Len(t, doc.Packages, 5)
+	assert.Len(t, doc.Relationships, 5)
+
+	foo := doc.Packages[1]
+	assert.Equal(t, "SPDXRef-Package-js-foo-1.2.3", foo.SPDXID)
+	assert.Equal(t, "MIT", foo.LicenseDeclared)
+	assert.Equal(t, "Person: foo author (foo@example.com)", foo.Originator)
+	assert.Equal(t, "https://foo.example.com", foo.Homepage)
+	assert.Equal(t, []spdxChecksum{
+		{Algorithm: "SHA1", ChecksumValue: "62ce07900e6b17d6081c9dc5d4cdc79f6169ece7"},
+	}, foo.Checksums)
+	assert.Contains(t, foo.Comment, "Directory hash: h1:TUdaB1b96Dyx0QsdpzRUgfHlBwfjMQxkxniQxvuqf60=")
+	assert.Equal(t, []spdxExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/foo@1.2.3"},
+	}, foo.ExternalRefs)
+
+	assert.Equal(t, "SPDXRef-Package-js-acme-ui-2.1.0", doc.Packages[2].SPDXID)
+	assert.Equal(t, "LicenseRef-acme-ui-EULA.md", doc.Packages[2].LicenseDeclared)
+	assert.Equal(t, "LicenseRef-UNLICENSED", doc.Packages[3].LicenseDeclared)
+	assert.Equal(t, "(MIT OR Apache-2.0)", doc.Packages[4].LicenseDeclared)
+	assert.Equal(t, []spdxExtractedLicense{
+		{LicenseID: "LicenseRef-acme-ui-EULA.md", ExtractedText: "ACME END USER LICENSE AGREEMENT", Name: "EULA.md"},
+		{LicenseID: "LicenseRef-UNLICENSED", ExtractedText: "This package is not licensed for use by others (UNLICENSED).", Name: "UNLICENSED"},
+	}, doc.ExtractedLicenseInfo)
+}
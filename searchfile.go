@@ -2,6 +2,7 @@ package linkedpackage
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,14 +10,24 @@ import (
 
 func Search(dir string, extensions ...string) []string {
 	result := []string{}
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	for _, name := range SearchFS(DirFS(dir), ".", extensions...) {
+		result = append(result, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	return result
+}
+
+// SearchFS returns slash separated paths of the files in dir of the file system that have one of the extensions.
+func SearchFS(fsys fs.FS, dir string, extensions ...string) []string {
+	result := []string{}
+	fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			fmt.Printf("prevent panic by handling failure accessing a path %q: %v\n", path, err)
+			// stdout is used for the output (e.g. SBOM)
+			fmt.Fprintf(os.Stderr, "prevent panic by handling failure accessing a path %q: %v\n", path, err)
 			return err
 		}
-		if !info.IsDir() {
+		if !d.IsDir() {
 			for _, extension := range extensions {
-				if strings.HasSuffix(info.Name(), extension) {
+				if strings.HasSuffix(d.Name(), extension) {
 					result = append(result, path)
 				}
 			}
@@ -24,4 +35,4 @@ func Search(dir string, extensions ...string) []string {
 		return nil
 	})
 	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,302 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is npm's version range like ">=1.2.3 <2.0.0 || ^3.0.0".
+//
+// See https://github.com/npm/node-semver#ranges
+type Range struct {
+	// sets are joined by "||" and comparators in a set are joined by AND.
+	sets [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+func (c comparator) test(v Version) bool {
+	r := v.Compare(c.version)
+	switch c.op {
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	}
+	return r == 0
+}
+
+// ParseRange parses npm's range syntax.
+func ParseRange(src string) (Range, error) {
+	var result Range
+	for _, setSrc := range strings.Split(src, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(setSrc))
+		if err != nil {
+			return Range{}, fmt.Errorf("semver: invalid range %q: %w", src, err)
+		}
+		result.sets = append(result.sets, set)
+	}
+	return result, nil
+}
+
+// MustParseRange is like ParseRange but panics if the range is invalid.
+func MustParseRange(src string) Range {
+	r, err := ParseRange(src)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Contains returns true if the version satisfies the range.
+// Prerelease versions satisfy only comparators that have prerelease on the same major.minor.patch (same as npm).
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		if testSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func testSet(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.test(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 {
+		return true
+	}
+	for _, c := range set {
+		cv := c.version
+		if len(cv.Prerelease) > 0 && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies parses the version and the range, and checks the version satisfies the range.
+func Satisfies(version, rangeSrc string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	r, err := ParseRange(rangeSrc)
+	if err != nil {
+		return false, err
+	}
+	return r.Contains(v), nil
+}
+
+// partial is the version that may have wildcards ("1.x", "1.2", "*").
+type partial struct {
+	numbers    [3]int
+	specified  int
+	prerelease []string
+}
+
+func (p partial) version() Version {
+	return Version{Major: p.numbers[0], Minor: p.numbers[1], Patch: p.numbers[2], Prerelease: p.prerelease}
+}
+
+// ceil returns the lowest version that is greater than any version that matches the partial.
+func (p partial) ceil() Version {
+	switch p.specified {
+	case 1:
+		return Version{Major: p.numbers[0] + 1, Prerelease: []string{"0"}}
+	case 2:
+		return Version{Major: p.numbers[0], Minor: p.numbers[1] + 1, Prerelease: []string{"0"}}
+	}
+	return Version{}
+}
+
+func parsePartial(src string) (partial, error) {
+	var result partial
+	s := strings.TrimLeft(strings.TrimSpace(src), "=v")
+	if i := strings.Index(s, "+"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i != -1 {
+		result.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	if s == "" {
+		return result, nil
+	}
+	fragments := strings.Split(s, ".")
+	if len(fragments) > 3 {
+		return result, fmt.Errorf("invalid version %q", src)
+	}
+	for i, fragment := range fragments {
+		if fragment == "x" || fragment == "X" || fragment == "*" {
+			break
+		}
+		n, err := strconv.Atoi(fragment)
+		if err != nil || n < 0 {
+			return result, fmt.Errorf("invalid version %q", src)
+		}
+		result.numbers[i] = n
+		result.specified = i + 1
+	}
+	if result.specified < 3 {
+		// prerelease is meaningful only for full versions
+		result.prerelease = nil
+	}
+	return result, nil
+}
+
+func parseComparatorSet(src string) ([]comparator, error) {
+	// hyphen range: "1.2.3 - 2.3.4"
+	if fields := strings.Fields(src); len(fields) == 3 && fields[1] == "-" {
+		from, err := parsePartial(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parsePartial(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		var result []comparator
+		if from.specified > 0 {
+			result = append(result, comparator{op: ">=", version: from.version()})
+		}
+		switch {
+		case to.specified == 3:
+			result = append(result, comparator{op: "<=", version: to.version()})
+		case to.specified > 0:
+			result = append(result, comparator{op: "<", version: to.ceil()})
+		}
+		return anyIfEmpty(result), nil
+	}
+
+	// operators may be separated from the version by spaces (e.g. ">= 1.2.3")
+	var tokens []string
+	for _, field := range strings.Fields(src) {
+		if len(tokens) > 0 && isOperator(tokens[len(tokens)-1]) {
+			tokens[len(tokens)-1] += field
+		} else {
+			tokens = append(tokens, field)
+		}
+	}
+	var result []comparator
+	for _, token := range tokens {
+		comparators, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, comparators...)
+	}
+	return anyIfEmpty(result), nil
+}
+
+func isOperator(s string) bool {
+	switch s {
+	case "<", "<=", ">", ">=", "=", "~", "^", "~>":
+		return true
+	}
+	return false
+}
+
+func anyIfEmpty(comparators []comparator) []comparator {
+	if len(comparators) == 0 {
+		return []comparator{{op: ">=", version: Version{}}}
+	}
+	return comparators
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{"<=", ">=", "~>", "<", ">", "=", "~", "^"} {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+	p, err := parsePartial(token[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	lower := p.version()
+	switch op {
+	case "", "=":
+		if p.specified == 3 {
+			return []comparator{{op: "=", version: lower}}, nil
+		}
+		return xRange(p), nil
+	case "~", "~>":
+		if p.specified == 3 {
+			return []comparator{
+				{op: ">=", version: lower},
+				{op: "<", version: Version{Major: p.numbers[0], Minor: p.numbers[1] + 1, Prerelease: []string{"0"}}},
+			}, nil
+		}
+		return xRange(p), nil
+	case "^":
+		return caretRange(p), nil
+	case ">":
+		switch p.specified {
+		case 0:
+			// nothing is greater than "*"
+			return []comparator{{op: "<", version: Version{Prerelease: []string{"0"}}}}, nil
+		case 3:
+			return []comparator{{op: ">", version: lower}}, nil
+		}
+		return []comparator{{op: ">=", version: p.ceil()}}, nil
+	case ">=":
+		return []comparator{{op: ">=", version: lower}}, nil
+	case "<":
+		return []comparator{{op: "<", version: lower}}, nil
+	case "<=":
+		switch p.specified {
+		case 0:
+			return anyIfEmpty(nil), nil
+		case 3:
+			return []comparator{{op: "<=", version: lower}}, nil
+		}
+		return []comparator{{op: "<", version: p.ceil()}}, nil
+	}
+	return nil, fmt.Errorf("invalid comparator %q", token)
+}
+
+// xRange converts "1.x" into ">=1.0.0 <2.0.0-0".
+func xRange(p partial) []comparator {
+	if p.specified == 0 {
+		return anyIfEmpty(nil)
+	}
+	return []comparator{
+		{op: ">=", version: p.version()},
+		{op: "<", version: p.ceil()},
+	}
+}
+
+// caretRange converts "^1.2.3" into ">=1.2.3 <2.0.0-0". It allows changes that don't modify the left-most non-zero number.
+func caretRange(p partial) []comparator {
+	if p.specified == 0 {
+		return anyIfEmpty(nil)
+	}
+	lower := p.version()
+	var upper Version
+	switch {
+	case p.numbers[0] != 0 || p.specified == 1:
+		upper = Version{Major: p.numbers[0] + 1}
+	case p.numbers[1] != 0 || p.specified == 2:
+		upper = Version{Minor: p.numbers[1] + 1}
+	default:
+		upper = Version{Patch: p.numbers[2] + 1}
+	}
+	upper.Prerelease = []string{"0"}
+	return []comparator{
+		{op: ">=", version: lower},
+		{op: "<", version: upper},
+	}
+}
@@ -0,0 +1,62 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_Contains(t *testing.T) {
+	tests := []struct {
+		rangeSrc string
+		version  string
+		want     bool
+	}{
+		{rangeSrc: "<4.17.19", version: "4.17.15", want: true},
+		{rangeSrc: "<4.17.19", version: "4.17.19", want: false},
+		{rangeSrc: "<=1.6.7", version: "1.6.7", want: true},
+		{rangeSrc: ">=1.0.0 <2.0.0", version: "1.9.9", want: true},
+		{rangeSrc: ">=1.0.0 <2.0.0", version: "2.0.0", want: false},
+		{rangeSrc: ">= 1.0.0 < 2.0.0", version: "1.5.0", want: true},
+		{rangeSrc: "4.0.0-alpha.0 - 4.5.12", version: "4.5.12", want: true},
+		{rangeSrc: "4.0.0-alpha.0 - 4.5.12", version: "4.5.13", want: false},
+		{rangeSrc: "1.2 - 2.3", version: "2.3.9", want: true},
+		{rangeSrc: "1.2 - 2.3", version: "2.4.0", want: false},
+		{rangeSrc: "^1.2.3", version: "1.9.0", want: true},
+		{rangeSrc: "^1.2.3", version: "2.0.0", want: false},
+		{rangeSrc: "^0.2.3", version: "0.2.9", want: true},
+		{rangeSrc: "^0.2.3", version: "0.3.0", want: false},
+		{rangeSrc: "^0.0.3", version: "0.0.4", want: false},
+		{rangeSrc: "~1.2.3", version: "1.2.9", want: true},
+		{rangeSrc: "~1.2.3", version: "1.3.0", want: false},
+		{rangeSrc: "1.x", version: "1.99.0", want: true},
+		{rangeSrc: "1.x", version: "2.0.0", want: false},
+		{rangeSrc: ">1.2", version: "1.2.9", want: false},
+		{rangeSrc: ">1.2", version: "1.3.0", want: true},
+		{rangeSrc: "<=1.2", version: "1.2.9", want: true},
+		{rangeSrc: "*", version: "0.0.1", want: true},
+		{rangeSrc: "", version: "0.0.1", want: true},
+		{rangeSrc: "<1.0.2 || >=2.0.0 <2.2.2", version: "2.2.1", want: true},
+		{rangeSrc: "<1.0.2 || >=2.0.0 <2.2.2", version: "1.5.0", want: false},
+		{rangeSrc: "1.2.3", version: "1.2.3", want: true},
+		{rangeSrc: "=1.2.3", version: "1.2.4", want: false},
+		// prerelease is matched only when the comparator has the same version tuple
+		{rangeSrc: ">1.2.3-alpha.3", version: "1.2.3-alpha.7", want: true},
+		{rangeSrc: ">1.2.3-alpha.3", version: "3.4.5-alpha.9", want: false},
+		{rangeSrc: "<2.0.0", version: "1.0.0-beta", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rangeSrc+" "+tt.version, func(t *testing.T) {
+			got, err := Satisfies(tt.version, tt.rangeSrc)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRange_error(t *testing.T) {
+	for _, src := range []string{"abc", ">=1.2.3.4", "^a.b"} {
+		_, err := ParseRange(src)
+		assert.Error(t, err, src)
+	}
+}
@@ -0,0 +1,126 @@
+// Package semver parses and compares semantic versions (https://semver.org).
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the parsed semantic version.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// Parse parses version string. Leading "v" or "=" is accepted and
+// missing minor and patch numbers are treated as 0.
+func Parse(src string) (Version, error) {
+	var result Version
+	s := strings.TrimSpace(src)
+	s = strings.TrimLeft(s, "=v")
+	if i := strings.Index(s, "+"); i != -1 {
+		result.Build = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i != -1 {
+		result.Prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	fragments := strings.Split(s, ".")
+	if len(fragments) > 3 || fragments[0] == "" {
+		return Version{}, fmt.Errorf("semver: invalid version %q", src)
+	}
+	numbers := []*int{&result.Major, &result.Minor, &result.Patch}
+	for i, fragment := range fragments {
+		n, err := strconv.Atoi(fragment)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("semver: invalid version %q", src)
+		}
+		*numbers[i] = n
+	}
+	return result, nil
+}
+
+// MustParse is like Parse but panics if the version is invalid.
+func MustParse(src string) Version {
+	v, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (v Version) String() string {
+	result := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		result += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		result += "+" + v.Build
+	}
+	return result
+}
+
+// Compare returns -1, 0 or 1. Build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	// a version without prerelease has higher precedence
+	if len(v.Prerelease) == 0 || len(o.Prerelease) == 0 {
+		return -compareInt(len(v.Prerelease), len(o.Prerelease))
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := comparePrerelease(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.Prerelease), len(o.Prerelease))
+}
+
+// Compare parses and compares two versions. It returns error if any version is invalid.
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func comparePrerelease(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(na, nb)
+	case errA == nil:
+		// numeric identifiers have lower precedence
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,61 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    Version
+		wantErr bool
+	}{
+		{src: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{src: "v1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{src: "1.2", want: Version{Major: 1, Minor: 2}},
+		{src: "0", want: Version{}},
+		{src: "1.0.0-beta.2+exp.sha.5114f85", want: Version{Major: 1, Prerelease: []string{"beta", "2"}, Build: "exp.sha.5114f85"}},
+		{src: "1.x", wantErr: true},
+		{src: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := Parse(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// ordered example from semver.org
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		c, err := Compare(ordered[i], ordered[i+1])
+		assert.NoError(t, err)
+		assert.Equal(t, -1, c, "%s < %s", ordered[i], ordered[i+1])
+		c, err = Compare(ordered[i+1], ordered[i])
+		assert.NoError(t, err)
+		assert.Equal(t, 1, c, "%s > %s", ordered[i+1], ordered[i])
+	}
+	c, err := Compare("1.0.0+build1", "1.0.0+build2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c)
+}
@@ -0,0 +1,2 @@
+/*! For license information please see main.js.LICENSE.txt */
+(()=>{var e={};/*! jQuery v3.7.0 | (c) OpenJS Foundation and other contributors | jquery.org/license */e.a=1;/*! app v1.0.0 built by CI */e.b=2;/* internal comment */})();
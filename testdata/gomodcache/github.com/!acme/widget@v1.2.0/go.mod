@@ -0,0 +1,3 @@
+module github.com/Acme/widget
+
+go 1.16
@@ -0,0 +1,207 @@
+package linkedpackage
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// tomlTable is the table of TOML file. Values are string, []string or raw text of other types.
+type tomlTable struct {
+	// Name is the table name like "package" or "dependencies.log"
+	Name string
+	// Array is true for the array of tables ([[name]])
+	Array  bool
+	Values map[string]interface{}
+}
+
+// parseTOML parses the subset of TOML that is used by Cargo.toml and Cargo.lock: tables, arrays of tables,
+// strings (including multi-line ones) and arrays of strings. Inline tables and other value types are kept as raw text.
+func parseTOML(r io.Reader) ([]tomlTable, error) {
+	result := []tomlTable{{Values: map[string]interface{}{}}}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	var key string
+	var pending strings.Builder
+	inValue := false
+	for s.Scan() {
+		line := s.Text()
+		if inValue {
+			pending.WriteString("\n")
+			pending.WriteString(line)
+			if !tomlValueComplete(pending.String()) {
+				continue
+			}
+			result[len(result)-1].Values[key] = parseTOMLValue(pending.String())
+			inValue = false
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "[["):
+			name := strings.TrimSpace(strings.Trim(stripTOMLComment(trimmed), "[]"))
+			result = append(result, tomlTable{Name: name, Array: true, Values: map[string]interface{}{}})
+		case strings.HasPrefix(trimmed, "["):
+			name := strings.TrimSpace(strings.Trim(stripTOMLComment(trimmed), "[]"))
+			result = append(result, tomlTable{Name: name, Values: map[string]interface{}{}})
+		default:
+			i := strings.Index(trimmed, "=")
+			if i == -1 {
+				continue
+			}
+			key = strings.Trim(strings.TrimSpace(trimmed[:i]), `"'`)
+			value := strings.TrimSpace(trimmed[i+1:])
+			if !tomlValueComplete(value) {
+				pending.Reset()
+				pending.WriteString(value)
+				inValue = true
+				continue
+			}
+			result[len(result)-1].Values[key] = parseTOMLValue(value)
+		}
+	}
+	return result, s.Err()
+}
+
+// tomlFind returns the first table of the name.
+func tomlFind(tables []tomlTable, name string) (tomlTable, bool) {
+	for _, table := range tables {
+		if table.Name == name {
+			return table, true
+		}
+	}
+	return tomlTable{}, false
+}
+
+func (t tomlTable) String(key string) string {
+	value, _ := t.Values[key].(string)
+	return value
+}
+
+func (t tomlTable) Strings(key string) []string {
+	switch value := t.Values[key].(type) {
+	case []string:
+		return value
+	case string:
+		return []string{value}
+	}
+	return nil
+}
+
+// tomlValueComplete checks whether the value continues to the next line (multi-line string or array).
+func tomlValueComplete(value string) bool {
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) {
+			return strings.Count(value, quote) >= 2
+		}
+	}
+	if strings.HasPrefix(value, "[") {
+		depth := 0
+		var quote byte
+		for i := 0; i < len(value); i++ {
+			c := value[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#':
+				// comment until the end of line
+				for i < len(value) && value[i] != '\n' {
+					i++
+				}
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+			}
+		}
+		return depth == 0
+	}
+	return true
+}
+
+func parseTOMLValue(value string) interface{} {
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) {
+			content := strings.TrimPrefix(value, quote)
+			if i := strings.Index(content, quote); i != -1 {
+				content = content[:i]
+			}
+			return strings.TrimPrefix(content, "\n")
+		}
+	}
+	if strings.HasPrefix(value, "[") {
+		var result []string
+		rest := value[1:]
+		for {
+			str, remain, ok := nextTOMLString(rest)
+			if !ok {
+				break
+			}
+			result = append(result, str)
+			rest = remain
+		}
+		return result
+	}
+	if str, _, ok := nextTOMLString(value); ok && (value[0] == '"' || value[0] == '\'') {
+		return str
+	}
+	return stripTOMLComment(value)
+}
+
+// nextTOMLString finds the next basic or literal string. Comments are skipped.
+func nextTOMLString(src string) (string, string, bool) {
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '#':
+			j := strings.Index(src[i:], "\n")
+			if j == -1 {
+				return "", "", false
+			}
+			i += j
+		case '\'':
+			j := strings.Index(src[i+1:], "'")
+			if j == -1 {
+				return "", "", false
+			}
+			return src[i+1 : i+1+j], src[i+2+j:], true
+		case '"':
+			var b strings.Builder
+			for j := i + 1; j < len(src); j++ {
+				c := src[j]
+				if c == '"' {
+					return b.String(), src[j+1:], true
+				}
+				if c == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(src[j])
+					}
+					continue
+				}
+				b.WriteByte(c)
+			}
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+func stripTOMLComment(src string) string {
+	if i := strings.Index(src, "#"); i != -1 {
+		src = src[:i]
+	}
+	return strings.TrimSpace(src)
+}